@@ -1,5 +1,6 @@
 import React, { useState, useEffect } from "react";
 import MediaCapture from "./MediaCapture";
+import PipelineProgress from "./PipelineProgress";
 
 interface MainPageProps {
   email: string;
@@ -10,6 +11,9 @@ const MainPage: React.FC<MainPageProps> = ({ email }) => {
   const currentTime = new Date();
   const [isRecording, setIsRecording] = useState(false);
   const [meetingId, setMeetingId] = useState<string | null>(null);
+  const [endedMeetingId, setEndedMeetingId] = useState<string | null>(null);
+  const [meetingToken, setMeetingToken] = useState<string | null>(null);
+  const [reportToken, setReportToken] = useState<string | null>(null);
   const [selectedHour, setSelectedHour] = useState<number>(currentTime.getHours());
   const [selectedMinute, setSelectedMinute] = useState<number>(currentTime.getMinutes());
   const [timeToStart, setTimeToStart] = useState<number | null>(null);
@@ -45,6 +49,7 @@ const MainPage: React.FC<MainPageProps> = ({ email }) => {
             method: "POST",
             headers: {
               "Content-Type": "application/json",
+              Authorization: `Bearer ${meetingToken}`,
             },
             body: JSON.stringify({ meeting_id: meetingId }),
           });
@@ -55,6 +60,7 @@ const MainPage: React.FC<MainPageProps> = ({ email }) => {
 
           const data = await response.json();
           console.log(data.message);
+          setEndedMeetingId(meetingId);
         } catch (error) {
           console.error("Error ending the meeting:", error);
         }
@@ -70,6 +76,8 @@ const MainPage: React.FC<MainPageProps> = ({ email }) => {
         const data = await response.json();
         if (data?.meeting_id) {
           setMeetingId(data.meeting_id);
+          setMeetingToken(data.token ?? null);
+          setReportToken(data.report_token ?? null);
         } else {
           throw new Error("Invalid response format");
         }
@@ -160,13 +168,22 @@ const MainPage: React.FC<MainPageProps> = ({ email }) => {
             </div>
           </div>
 
-          <MediaCapture isRecording={isRecording} meetingId={meetingId} />
+          <MediaCapture isRecording={isRecording} meetingId={meetingId} meetingToken={meetingToken} />
 
           {meetingId && (
             <p className="mt-4 text-lg text-green-400">
               Your Meeting ID: <span className="font-bold">{meetingId}</span>
             </p>
           )}
+
+          {!meetingId && endedMeetingId && (
+            <PipelineProgress
+              key={endedMeetingId}
+              meetingId={endedMeetingId}
+              meetingToken={meetingToken}
+              reportToken={reportToken}
+            />
+          )}
         </div>
 
         {/* Arrow Between Columns */}
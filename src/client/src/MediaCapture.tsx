@@ -8,6 +8,7 @@ declare class ImageCapture {
 interface MediaCaptureProps {
   isRecording: boolean;
   meetingId: string | null;
+  meetingToken: string | null;
 }
 
 // CONF
@@ -15,7 +16,7 @@ const SCREENSHOT_INTERVAL_MS = 2000; // 2 sek
 const AUDIO_CAPTURE_INTERVAL_MS = 20000; // 15 sek
 const FRAME_CHANGE_THRESHOLD = 0.01; //10 %
 
-const MediaCapture: React.FC<MediaCaptureProps> = ({ isRecording, meetingId }) => {
+const MediaCapture: React.FC<MediaCaptureProps> = ({ isRecording, meetingId, meetingToken }) => {
   const canvasRef = useRef<HTMLCanvasElement | null>(null);
   const previousFrameRef = useRef<ImageData | null>(null);
   const mediaStreamRef = useRef<MediaStream | null>(null);
@@ -108,6 +109,7 @@ const MediaCapture: React.FC<MediaCaptureProps> = ({ isRecording, meetingId }) =
                   try {
                     const response = await fetch("http://127.0.0.1:8080/capture-screenshots", {
                       method: "POST",
+                      headers: { Authorization: `Bearer ${meetingToken}` },
                       body: formData,
                     });
                     const result = await response.text();
@@ -178,6 +180,7 @@ const MediaCapture: React.FC<MediaCaptureProps> = ({ isRecording, meetingId }) =
       try {
         const response = await fetch("http://127.0.0.1:8080/capture-audio", {
           method: "POST",
+          headers: { Authorization: `Bearer ${meetingToken}` },
           body: formData,
         });
         const result = await response.text();
@@ -0,0 +1,143 @@
+import React, { useEffect, useState } from "react";
+
+interface PipelineProgressProps {
+  meetingId: string;
+  meetingToken: string | null;
+  reportToken: string | null;
+}
+
+interface PipelineEvent {
+  meeting_id: string;
+  type: string;
+  task_id?: string;
+  task_type?: string;
+  status?: string;
+  error?: string;
+  timestamp: string;
+}
+
+interface TaskInfo {
+  task_type: string;
+  status: string;
+}
+
+// First event of the stream: the meeting status when the client subscribed
+interface MeetingSnapshot {
+  status: string;
+  tasks: TaskInfo[] | null;
+}
+
+// Ordered stages of the pipeline after the meeting has ended
+const STAGES = [
+  { event: "meeting_ended", label: "Meeting ended" },
+  { event: "summary_dispatched", label: "Transcription done" },
+  { event: "summary_completed", label: "Summary ready" },
+  { event: "report_completed", label: "Report generated" },
+  { event: "email_completed", label: "Email sent" },
+];
+
+// Task types whose failure is only reported while workers retry them
+const TASK_TYPES = ["transcription", "ocr", "summary", "report", "email"];
+
+// Events after which the pipeline will not make progress any more
+const FAILURE_EVENTS = ["pipeline_failed", "import_failed"];
+
+// Index of the furthest stage reached according to a snapshot
+const snapshotStage = (snapshot: MeetingSnapshot): number => {
+  const tasks = snapshot.tasks ?? [];
+  const has = (type: string, status?: string) =>
+    tasks.some((task) => task.task_type === type && (!status || task.status === status));
+
+  const stage = (event: string) => STAGES.findIndex((s) => s.event === event);
+
+  if (has("email", "completed")) return stage("email_completed");
+  if (has("report", "completed")) return stage("report_completed");
+  if (has("summary", "completed")) return stage("summary_completed");
+  if (has("summary")) return stage("summary_dispatched");
+  return 0;
+};
+
+const PipelineProgress: React.FC<PipelineProgressProps> = ({ meetingId, meetingToken, reportToken }) => {
+  const [stageIndex, setStageIndex] = useState<number>(0);
+  const [failed, setFailed] = useState<boolean>(false);
+  const [retrying, setRetrying] = useState<boolean>(false);
+
+  useEffect(() => {
+    const source = new EventSource(`http://127.0.0.1:8080/meetings/${meetingId}/events?token=${meetingToken}`);
+
+    const advance = (index: number) => {
+      setStageIndex((current) => Math.max(current, index));
+      setRetrying(false);
+    };
+
+    const handleSnapshot = (e: MessageEvent) => {
+      const snapshot: MeetingSnapshot = JSON.parse(e.data);
+      advance(snapshotStage(snapshot));
+      if (snapshot.status === "failed") {
+        setFailed(true);
+      }
+    };
+
+    const handleEvent = (e: MessageEvent) => {
+      const event: PipelineEvent = JSON.parse(e.data);
+      if (FAILURE_EVENTS.includes(event.type)) {
+        console.error("Pipeline failed:", event.error);
+        setFailed(true);
+        return;
+      }
+
+      // Workers report a failed attempt and then retry the task
+      if (event.status === "failed") {
+        setRetrying(true);
+        return;
+      }
+
+      const index = STAGES.findIndex((stage) => stage.event === event.type);
+      if (index >= 0) {
+        advance(index);
+      }
+    };
+
+    source.addEventListener("snapshot", handleSnapshot);
+    [
+      ...STAGES.map((stage) => stage.event),
+      ...TASK_TYPES.map((type) => `${type}_failed`),
+      ...FAILURE_EVENTS,
+    ].forEach((type) => source.addEventListener(type, handleEvent));
+
+    source.onerror = (err) => {
+      console.error("Pipeline event stream error:", err);
+    };
+
+    return () => source.close();
+  }, [meetingId, meetingToken]);
+
+  const reportIndex = STAGES.findIndex((stage) => stage.event === "report_completed");
+  const percent = Math.round(((stageIndex + 1) / STAGES.length) * 100);
+
+  return (
+    <div className="mt-6 w-full">
+      <p className="text-sm text-neutral-400 mb-2">
+        {failed
+          ? "Report generation failed"
+          : `${STAGES[stageIndex].label}${retrying ? " (retrying a failed step...)" : ""}`}
+      </p>
+      <div className="w-full bg-neutral-700 rounded-full h-3">
+        <div
+          className={`${failed ? "bg-red-500" : "bg-purple-500"} h-3 rounded-full transition-all duration-500`}
+          style={{ width: `${percent}%` }}
+        />
+      </div>
+      {reportToken && stageIndex >= reportIndex && (
+        <a
+          href={`http://127.0.0.1:8080/meetings/${meetingId}/report?token=${reportToken}`}
+          className="mt-3 inline-block text-amber-400 underline"
+        >
+          Download report
+        </a>
+      )}
+    </div>
+  );
+};
+
+export default PipelineProgress;
@@ -0,0 +1,60 @@
+package contracts
+
+import "fmt"
+
+const (
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+// AckMessage reports the outcome of a task back to the orchestrator.
+type AckMessage struct {
+	SchemaVersion int    `json:"schema_version"`
+	MeetingId     string `json:"meeting_id"`
+	TaskId        string `json:"task_id"`
+	TaskType      string `json:"task_type"`
+	Status        string `json:"status"`
+	WorkerId      string `json:"worker_id,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// NewAckMessage builds an acknowledgement for a task. A non-nil taskErr is
+// recorded as the error of the attempt.
+func NewAckMessage(task TaskMessage, status, workerId string, taskErr error) AckMessage {
+	ack := AckMessage{
+		SchemaVersion: SchemaVersion,
+		MeetingId:     task.MeetingId,
+		TaskId:        task.TaskId,
+		TaskType:      task.TaskType,
+		Status:        status,
+		WorkerId:      workerId,
+	}
+	if taskErr != nil {
+		ack.Error = taskErr.Error()
+	}
+	return ack
+}
+
+func DecodeAckMessage(body []byte) (AckMessage, error) {
+	var ack AckMessage
+	err := decode(body, &ack)
+	return ack, err
+}
+
+func (m *AckMessage) version() int {
+	return m.SchemaVersion
+}
+
+func (m *AckMessage) validate() error {
+	switch {
+	case m.MeetingId == "":
+		return missingField("meeting_id")
+	case m.TaskId == "":
+		return missingField("task_id")
+	case m.TaskType == "":
+		return missingField("task_type")
+	case m.Status != StatusCompleted && m.Status != StatusFailed:
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidMessage, m.Status)
+	}
+	return nil
+}
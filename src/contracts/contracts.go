@@ -0,0 +1,50 @@
+// Package contracts defines the messages exchanged over RabbitMQ between the
+// Go services. Every message carries a schema version so consumers can reject
+// payloads they do not understand instead of acting on half-parsed data.
+package contracts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// SchemaVersion is the version stamped on every message produced by this
+// package. Bump it whenever a field changes meaning or becomes required.
+const SchemaVersion = 1
+
+var (
+	ErrUnsupportedVersion = errors.New("unsupported schema version")
+	ErrInvalidMessage     = errors.New("invalid message")
+)
+
+type message interface {
+	version() int
+	validate() error
+}
+
+// Encode validates a message and marshals it to JSON.
+func Encode(msg message) ([]byte, error) {
+	if err := checkMessage(msg); err != nil {
+		return nil, err
+	}
+	return json.Marshal(msg)
+}
+
+func decode(body []byte, msg message) error {
+	if err := json.Unmarshal(body, msg); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+	return checkMessage(msg)
+}
+
+func checkMessage(msg message) error {
+	if msg.version() != SchemaVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedVersion, msg.version())
+	}
+	return msg.validate()
+}
+
+func missingField(name string) error {
+	return fmt.Errorf("%w: missing %s", ErrInvalidMessage, name)
+}
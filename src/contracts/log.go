@@ -0,0 +1,44 @@
+package contracts
+
+import "time"
+
+// LogMessage is published to logs_queue and stored by the logger service.
+type LogMessage struct {
+	SchemaVersion int                    `json:"schema_version" bson:"schema_version"`
+	Timestamp     string                 `json:"timestamp" bson:"timestamp"`
+	Service       string                 `json:"service" bson:"service"`
+	Level         string                 `json:"level" bson:"level"`
+	Message       string                 `json:"message" bson:"message"`
+	Details       map[string]interface{} `json:"details" bson:"details"`
+}
+
+func NewLogMessage(service, level, message string, details map[string]interface{}) LogMessage {
+	return LogMessage{
+		SchemaVersion: SchemaVersion,
+		Timestamp:     time.Now().UTC().Format(time.RFC3339),
+		Service:       service,
+		Level:         level,
+		Message:       message,
+		Details:       details,
+	}
+}
+
+func DecodeLogMessage(body []byte) (LogMessage, error) {
+	var logMessage LogMessage
+	err := decode(body, &logMessage)
+	return logMessage, err
+}
+
+func (m *LogMessage) version() int {
+	return m.SchemaVersion
+}
+
+func (m *LogMessage) validate() error {
+	switch {
+	case m.Service == "":
+		return missingField("service")
+	case m.Message == "":
+		return missingField("message")
+	}
+	return nil
+}
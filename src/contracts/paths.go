@@ -0,0 +1,23 @@
+package contracts
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Directories shared between the services. Task messages carry file paths
+// inside them, so every service has to resolve them the same way.
+const (
+	TranscriptionDir = "/shared-transcription"
+	OCRDir           = "/shared-ocr"
+	ReportDir        = "/shared-report"
+)
+
+// SharedDir returns dir moved under SHARED_DATA_ROOT if it is set, e.g. when
+// the services run outside their containers.
+func SharedDir(dir string) string {
+	if root := os.Getenv("SHARED_DATA_ROOT"); root != "" {
+		return filepath.Join(root, dir)
+	}
+	return dir
+}
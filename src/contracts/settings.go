@@ -0,0 +1,44 @@
+package contracts
+
+import "fmt"
+
+// SummarySettings choose the LLM that summarizes a meeting. The orchestrator
+// stores them with the meeting and the summary service applies them over its
+// deployment defaults, so every field is optional.
+type SummarySettings struct {
+	Provider    string   `json:"provider,omitempty" bson:"provider,omitempty"`
+	Model       string   `json:"model,omitempty" bson:"model,omitempty"`
+	Temperature *float64 `json:"temperature,omitempty" bson:"temperature,omitempty"`
+	MaxTokens   int      `json:"max_tokens,omitempty" bson:"max_tokens,omitempty"`
+}
+
+// Validate checks the ranges every provider accepts. Whether the provider
+// and model exist is only known to the summary service.
+func (s *SummarySettings) Validate() error {
+	if s.Temperature != nil && (*s.Temperature < 0 || *s.Temperature > 2) {
+		return fmt.Errorf("temperature must be between 0 and 2, got %v", *s.Temperature)
+	}
+	if s.MaxTokens < 0 {
+		return fmt.Errorf("max_tokens must not be negative, got %d", s.MaxTokens)
+	}
+	return nil
+}
+
+// Merge returns s with the fields set in override replaced.
+func (s SummarySettings) Merge(override SummarySettings) SummarySettings {
+	if override.Provider != "" && override.Provider != s.Provider {
+		s.Provider = override.Provider
+		// A model name only makes sense for the provider it was chosen for.
+		s.Model = ""
+	}
+	if override.Model != "" {
+		s.Model = override.Model
+	}
+	if override.Temperature != nil {
+		s.Temperature = override.Temperature
+	}
+	if override.MaxTokens != 0 {
+		s.MaxTokens = override.MaxTokens
+	}
+	return s
+}
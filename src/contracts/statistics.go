@@ -0,0 +1,43 @@
+package contracts
+
+import "time"
+
+// MeetingStatistics is the document the summary service computes from the
+// transcript and stores in the statistics collection, and the report shows.
+// Times are in seconds.
+type MeetingStatistics struct {
+	MeetingID       string  `bson:"meeting_id" json:"meeting_id"`
+	TotalWords      int     `bson:"total_words" json:"total_words"`
+	TalkTimeSeconds float64 `bson:"talk_time_seconds" json:"talk_time_seconds"`
+	Turns           int     `bson:"turns" json:"turns"`
+	// Overlaps counts turns that start before another speaker's turn ends.
+	Overlaps int `bson:"overlaps" json:"overlaps"`
+	// Interruptions counts the overlaps after which the other speaker stops
+	// before the overlapping turn ends, i.e. gives up the floor.
+	Interruptions    int                 `bson:"interruptions" json:"interruptions"`
+	LongestMonologue *Monologue          `bson:"longest_monologue,omitempty" json:"longest_monologue,omitempty"`
+	Speakers         []SpeakerStatistics `bson:"speakers" json:"speakers"`
+	CreatedAt        time.Time           `bson:"created_at" json:"created_at"`
+}
+
+type SpeakerStatistics struct {
+	SpeakerID          string  `bson:"speaker_id" json:"speaker_id"`
+	Words              int     `bson:"words" json:"words"`
+	TalkTimeSeconds    float64 `bson:"talk_time_seconds" json:"talk_time_seconds"`
+	Turns              int     `bson:"turns" json:"turns"`
+	AverageTurnWords   float64 `bson:"average_turn_words" json:"average_turn_words"`
+	AverageTurnSeconds float64 `bson:"average_turn_seconds" json:"average_turn_seconds"`
+	LongestTurnSeconds float64 `bson:"longest_turn_seconds" json:"longest_turn_seconds"`
+	// Interruptions the speaker made and suffered.
+	Interruptions int `bson:"interruptions" json:"interruptions"`
+	Interrupted   int `bson:"interrupted" json:"interrupted"`
+}
+
+// Monologue is the longest uninterrupted turn of a meeting.
+type Monologue struct {
+	SpeakerID      string  `bson:"speaker_id" json:"speaker_id"`
+	TimestampStart string  `bson:"timestamp_start" json:"timestamp_start"`
+	TimestampEnd   string  `bson:"timestamp_end" json:"timestamp_end"`
+	Seconds        float64 `bson:"seconds" json:"seconds"`
+	Words          int     `bson:"words" json:"words"`
+}
@@ -0,0 +1,43 @@
+package contracts
+
+// TaskMessage is sent by the orchestrator to the worker queues. Fields other
+// than the identifiers are only set for the stages that need them.
+type TaskMessage struct {
+	SchemaVersion int      `json:"schema_version"`
+	MeetingId     string   `json:"meeting_id"`
+	TaskId        string   `json:"task_id"`
+	TaskType      string   `json:"task_type"`
+	FilePath      string   `json:"file_path,omitempty"`
+	Email         string   `json:"email,omitempty"`
+	Emails        []string `json:"emails,omitempty"`
+}
+
+func NewTaskMessage(meetingId, taskType string) TaskMessage {
+	return TaskMessage{
+		SchemaVersion: SchemaVersion,
+		MeetingId:     meetingId,
+		TaskType:      taskType,
+	}
+}
+
+func DecodeTaskMessage(body []byte) (TaskMessage, error) {
+	var task TaskMessage
+	err := decode(body, &task)
+	return task, err
+}
+
+func (m *TaskMessage) version() int {
+	return m.SchemaVersion
+}
+
+func (m *TaskMessage) validate() error {
+	switch {
+	case m.MeetingId == "":
+		return missingField("meeting_id")
+	case m.TaskId == "":
+		return missingField("task_id")
+	case m.TaskType == "":
+		return missingField("task_type")
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package contracts
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseTimestamp reads the "H:MM:SS.ffffff" offsets from the start of the
+// meeting that the transcription service writes to the transcriptions
+// collection.
+func ParseTimestamp(value string) (time.Duration, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid timestamp %q", value)
+	}
+
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid timestamp %q", value)
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid timestamp %q", value)
+	}
+	seconds, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timestamp %q", value)
+	}
+
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds*float64(time.Second)), nil
+}
+
+// FormatTimestamp writes an offset the way the transcription service does.
+func FormatTimestamp(d time.Duration) string {
+	d = d.Round(time.Microsecond)
+	hours := d / time.Hour
+	minutes := (d % time.Hour) / time.Minute
+	seconds := (d % time.Minute) / time.Second
+	micros := (d % time.Second) / time.Microsecond
+	return fmt.Sprintf("%d:%02d:%02d.%06d", hours, minutes, seconds, micros)
+}
@@ -1,4 +1,6 @@
 import logging
+import os
+import socket
 import pika
 import time
 import json
@@ -15,10 +17,11 @@ def process_message(ch, method, properties, body, ack_channel):
         message = json.loads(body)
         logging.info(f"Message received: {message}")
         file_path = message.get("file_path")
-        email = message.get("email")
+        emails = message.get("emails") or [message.get("email")]
+        emails = [email for email in emails if email]
         task_id = properties.correlation_id
 
-        if not file_path or not email:
+        if not file_path or not emails:
             logging.warning("Invalid message format. Skipping...")
             if ch.is_open:
                 ch.basic_nack(delivery_tag=method.delivery_tag, requeue=False)
@@ -26,9 +29,10 @@ def process_message(ch, method, properties, body, ack_channel):
                 logging.warning("Channel is closed. Cannot nack message.")
             return
 
-        logging.info(f"Received task for file: {file_path}, sending to: {email}")
+        logging.info(f"Received task for file: {file_path}, sending to: {', '.join(emails)}")
 
-        send_email(email, file_path)
+        for email in emails:
+            send_email(email, file_path)
 
         ack_message = {
             "meeting_id": message.get("meeting_id"),
@@ -61,6 +65,8 @@ def process_message(ch, method, properties, body, ack_channel):
 
 def send_ack_message(message, ack_channel):
     try:
+        message.setdefault("schema_version", 1)
+        message.setdefault("worker_id", socket.gethostname())
         ack_channel.queue_declare(queue='orchestrator_ack_queue', durable=True)
 
         ack_channel.basic_publish(
@@ -84,7 +90,7 @@ def main():
         try:
             logging.info("Connecting to RabbitMQ...")
             connection = pika.BlockingConnection(pika.ConnectionParameters(
-                host='rabbitmq',
+                host=os.environ.get('RABBITMQ_HOST', 'rabbitmq'),
                 port=5672,
                 credentials=credentials,
                 connection_attempts=5,
@@ -96,7 +102,11 @@ def main():
             ack_channel = connection.channel()
             logging.info("Connected to RabbitMQ")
 
-            channel.queue_declare(queue='email_queue', durable=True)
+            channel.queue_declare(
+                queue='email_queue',
+                durable=True,
+                arguments={'x-dead-letter-exchange': 'tasks.dlx'}
+            )
             logging.info("Queue declared.")
 
             channel.basic_qos(prefetch_count=1)
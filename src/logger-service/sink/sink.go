@@ -0,0 +1,46 @@
+package sink
+
+import (
+	"context"
+	"contracts"
+	"fmt"
+	"log"
+	"messaging"
+	"storage"
+)
+
+type Config struct {
+	Store  storage.Store
+	Broker messaging.Broker
+}
+
+// Start declares the log queue and starts storing the log messages sent to
+// it in the logger database.
+func (app *Config) Start() error {
+	if err := app.Broker.Declare(messaging.Queue{Name: "logs_queue"}); err != nil {
+		return err
+	}
+
+	app.Broker.Consume(messaging.Consumer{
+		Queue:   "logs_queue",
+		AutoAck: true,
+		Handler: app.handleLogMessage,
+	})
+	return nil
+}
+
+func (app *Config) handleLogMessage(d messaging.Delivery) {
+	logMessage, err := contracts.DecodeLogMessage(d.Body)
+	if err != nil {
+		log.Println("Rejecting log message:", err)
+		return
+	}
+
+	collection := app.Store.Collection("logger", "logs")
+	err = collection.InsertOne(context.Background(), logMessage)
+	if err != nil {
+		log.Println("Log insert error:", err)
+	} else {
+		fmt.Println("Log saved:", logMessage)
+	}
+}
@@ -0,0 +1,5 @@
+module messaging
+
+go 1.23.1
+
+require github.com/streadway/amqp v1.1.0
@@ -0,0 +1,46 @@
+package messaging
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+type HealthResponse struct {
+	Status string `json:"status"`
+	Broker Status `json:"broker"`
+}
+
+// HealthHandler reports the state of the broker connection. It answers 503
+// while the connection is being re-established so orchestration tools can
+// tell a service that lost its broker from a healthy one.
+func HealthHandler(broker Broker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response := HealthResponse{
+			Status: "ok",
+			Broker: broker.Status(),
+		}
+
+		code := http.StatusOK
+		if response.Broker.State != StateConnected {
+			response.Status = "degraded"
+			code = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+// ServeHealth exposes HealthHandler on /healthz for services that have no
+// HTTP server of their own.
+func ServeHealth(addr string, broker Broker) {
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", HealthHandler(broker))
+
+	log.Printf("Health endpoint listening on %s", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Printf("Health endpoint stopped: %v", err)
+	}
+}
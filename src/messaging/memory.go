@@ -0,0 +1,236 @@
+package messaging
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// MemoryBroker is an in-process Broker. Messages live only as long as the
+// process, so it is meant for running the pipeline locally and for exercising
+// it without a broker container. Queue semantics follow the RabbitMQ backend:
+// nacked messages are requeued at the head of the queue or dead-lettered, and
+// delay queues hand their messages to DelayTarget once Delay has passed.
+type MemoryBroker struct {
+	mu     sync.Mutex
+	queues map[string]*memoryQueue
+	closed bool
+	since  time.Time
+}
+
+type memoryQueue struct {
+	spec     Queue
+	messages []Message
+	ready    *sync.Cond
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		queues: make(map[string]*memoryQueue),
+		since:  time.Now(),
+	}
+}
+
+func (b *MemoryBroker) Declare(queues ...Queue) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+
+	for _, queue := range queues {
+		if queue.DeadLetter {
+			b.declare(Queue{Name: DeadLetterQueue(queue.Name)})
+		}
+		if err := b.declare(queue); err != nil {
+			log.Printf("Failed to declare queue '%s': %v", queue.Name, err)
+			return err
+		}
+		log.Printf("Queue '%s' declared successfully.", queue.Name)
+	}
+	return nil
+}
+
+// declare must be called with b.mu held.
+func (b *MemoryBroker) declare(queue Queue) error {
+	if existing, ok := b.queues[queue.Name]; ok {
+		if existing.spec != queue {
+			return ErrQueueMismatch
+		}
+		return nil
+	}
+
+	b.queues[queue.Name] = &memoryQueue{spec: queue, ready: sync.NewCond(&b.mu)}
+	return nil
+}
+
+func (b *MemoryBroker) Publish(queue string, msg Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+	return b.enqueue(queue, msg, false)
+}
+
+// enqueue must be called with b.mu held.
+func (b *MemoryBroker) enqueue(queue string, msg Message, front bool) error {
+	q, ok := b.queues[queue]
+	if !ok {
+		return ErrUnroutable
+	}
+
+	if q.spec.Delay > 0 {
+		time.AfterFunc(q.spec.Delay, func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			if b.closed {
+				return
+			}
+			if err := b.enqueue(q.spec.DelayTarget, msg, false); err != nil {
+				log.Printf("Dropping delayed message from '%s': %v", queue, err)
+			}
+		})
+		return nil
+	}
+
+	if front {
+		q.messages = append([]Message{msg}, q.messages...)
+	} else {
+		q.messages = append(q.messages, msg)
+	}
+	q.ready.Signal()
+	return nil
+}
+
+// nack must be called with b.mu held.
+func (b *MemoryBroker) nack(queue string, msg Message, requeue bool) {
+	if requeue {
+		b.enqueue(queue, msg, true)
+		return
+	}
+
+	q, ok := b.queues[queue]
+	if ok && q.spec.DeadLetter {
+		b.enqueue(DeadLetterQueue(queue), msg, false)
+	}
+}
+
+// Consume delivers messages from a declared queue until the broker is
+// closed. Consuming from an undeclared queue waits for it to be declared.
+func (b *MemoryBroker) Consume(consumer Consumer) {
+	go b.consume(consumer)
+}
+
+func (b *MemoryBroker) consume(consumer Consumer) {
+	var unsettled chan struct{}
+	if consumer.Prefetch > 0 && !consumer.AutoAck {
+		unsettled = make(chan struct{}, consumer.Prefetch)
+	}
+
+	log.Printf("Consuming messages from queue '%s'", consumer.Queue)
+	for {
+		if unsettled != nil {
+			unsettled <- struct{}{}
+		}
+
+		msg, ok := b.next(consumer.Queue)
+		if !ok {
+			return
+		}
+
+		consumer.Handler(b.newDelivery(consumer, msg, unsettled))
+	}
+}
+
+// next blocks until a message is available on queue, or returns false once
+// the broker is closed.
+func (b *MemoryBroker) next(queue string) (Message, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for {
+		if b.closed {
+			return Message{}, false
+		}
+
+		q, ok := b.queues[queue]
+		if !ok {
+			// Poll until the queue is declared.
+			b.mu.Unlock()
+			time.Sleep(minReconnectDelay)
+			b.mu.Lock()
+			continue
+		}
+
+		if len(q.messages) > 0 {
+			msg := q.messages[0]
+			q.messages = q.messages[1:]
+			return msg, true
+		}
+		q.ready.Wait()
+	}
+}
+
+func (b *MemoryBroker) newDelivery(consumer Consumer, msg Message, unsettled chan struct{}) Delivery {
+	d := Delivery{Message: msg, Queue: consumer.Queue}
+	if consumer.AutoAck {
+		return d
+	}
+
+	var once sync.Once
+	settle := func(requeue, ack bool) error {
+		settled := false
+		once.Do(func() {
+			settled = true
+			if !ack {
+				b.mu.Lock()
+				if !b.closed {
+					b.nack(consumer.Queue, msg, requeue)
+				}
+				b.mu.Unlock()
+			}
+			if unsettled != nil {
+				<-unsettled
+			}
+		})
+		if !settled {
+			return ErrSettled
+		}
+		return nil
+	}
+
+	d.ack = func() error { return settle(false, true) }
+	d.nack = func(requeue bool) error { return settle(requeue, false) }
+	return d
+}
+
+func (b *MemoryBroker) Status() Status {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state := StateConnected
+	if b.closed {
+		state = StateClosed
+	}
+	return Status{Backend: "memory", State: state, Since: b.since}
+}
+
+func (b *MemoryBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+	b.since = time.Now()
+
+	for _, q := range b.queues {
+		q.ready.Broadcast()
+	}
+	return nil
+}
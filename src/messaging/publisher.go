@@ -0,0 +1,134 @@
+package messaging
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+const confirmTimeout = 10 * time.Second
+
+// confirmPublisher publishes in confirm mode with mandatory routing. A publish only
+// succeeds once the broker has acknowledged the message and did not return it
+// as unroutable. Publishes are serialised so every call can match its own
+// confirmation. The channel is reopened on the next publish after it, or the
+// connection, was lost, or after a confirmation timed out.
+type confirmPublisher struct {
+	connection *Connection
+
+	mu       sync.Mutex
+	channel  *amqp.Channel
+	closed   chan *amqp.Error
+	confirms chan amqp.Confirmation
+	returns  chan amqp.Return
+	nextTag  uint64
+}
+
+// Publish sends msg to queue through the default exchange and waits for the
+// broker's verdict.
+func (p *confirmPublisher) Publish(queue string, msg amqp.Publishing) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.ensureChannel(); err != nil {
+		return err
+	}
+
+	// A return left over from a publish that timed out belongs to an older message.
+	p.drainReturns()
+
+	if msg.DeliveryMode == 0 {
+		msg.DeliveryMode = amqp.Persistent
+	}
+
+	err := p.channel.Publish("", queue, true, false, msg)
+	if err != nil {
+		p.resetChannel()
+		return err
+	}
+	tag := p.nextTag
+	p.nextTag++
+
+	timer := time.NewTimer(confirmTimeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case confirm, ok := <-p.confirms:
+			if !ok {
+				p.resetChannel()
+				return ErrChannelClosed
+			}
+			if confirm.DeliveryTag < tag {
+				continue
+			}
+			if !confirm.Ack {
+				return ErrNotConfirmed
+			}
+
+			// The broker sends basic.return before basic.ack, so a return for
+			// this message is already buffered when the ack arrives.
+			select {
+			case ret := <-p.returns:
+				return fmt.Errorf("%w: %s", ErrUnroutable, ret.ReplyText)
+			default:
+				return nil
+			}
+		case <-timer.C:
+			// The confirmation may still arrive, and nobody would read it or
+			// any later one, so the amqp reader would block on the full
+			// buffers and stall the connection. Give up on the channel
+			// instead; its buffers hold what is still outstanding. Closing
+			// waits for the broker, which is slow to answer right now.
+			go p.channel.Close()
+			p.channel = nil
+			return ErrConfirmTimeout
+		}
+	}
+}
+
+func (p *confirmPublisher) ensureChannel() error {
+	if p.channel != nil {
+		select {
+		case <-p.closed:
+			p.resetChannel()
+		default:
+			return nil
+		}
+	}
+
+	channel, err := p.connection.channel()
+	if err != nil {
+		return err
+	}
+	if err = channel.Confirm(false); err != nil {
+		channel.Close()
+		return fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+
+	p.channel = channel
+	p.closed = channel.NotifyClose(make(chan *amqp.Error, 1))
+	p.confirms = channel.NotifyPublish(make(chan amqp.Confirmation, 1))
+	p.returns = channel.NotifyReturn(make(chan amqp.Return, 1))
+	p.nextTag = 1
+	return nil
+}
+
+func (p *confirmPublisher) resetChannel() {
+	if p.channel != nil {
+		p.channel.Close()
+	}
+	p.channel = nil
+}
+
+func (p *confirmPublisher) drainReturns() {
+	for {
+		select {
+		case <-p.returns:
+		default:
+			return
+		}
+	}
+}
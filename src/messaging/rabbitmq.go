@@ -0,0 +1,431 @@
+package messaging
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+const (
+	minReconnectDelay  = time.Second
+	maxReconnectDelay  = 30 * time.Second
+	consumeRetryDelay  = 5 * time.Second
+	staleConnectionGap = 100 * time.Millisecond
+)
+
+// DeadLetterExchange receives messages nacked from queues declared with
+// DeadLetter. Each queue's dead-letter queue is bound to it with the queue
+// name as routing key. Workers outside this module declaring a task queue
+// must use the same x-dead-letter-exchange argument.
+const DeadLetterExchange = "tasks.dlx"
+
+// Connection is the RabbitMQ Broker. It keeps the connection alive: after the
+// broker goes away it re-dials, declares the queues again and re-subscribes
+// every registered consumer.
+type Connection struct {
+	uri       string
+	publisher *confirmPublisher
+
+	mu        sync.Mutex
+	conn      *amqp.Connection
+	queues    []Queue
+	state     State
+	lastError error
+	since     time.Time
+	ready     chan struct{}
+	done      chan struct{}
+}
+
+// Dial starts supervising a connection to uri and returns immediately.
+func Dial(uri string) *Connection {
+	c := &Connection{
+		uri:   uri,
+		state: StateConnecting,
+		since: time.Now(),
+		ready: make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	c.publisher = &confirmPublisher{connection: c}
+	go c.supervise()
+	return c
+}
+
+func (c *Connection) Declare(queues ...Queue) error {
+	c.mu.Lock()
+	c.queues = append(c.queues, queues...)
+	conn, state, lastError := c.conn, c.state, c.lastError
+	c.mu.Unlock()
+
+	if state == StateFailed {
+		return lastError
+	}
+	// Without a connection the queues are declared once it is established.
+	if state != StateConnected {
+		return nil
+	}
+
+	return declareOn(conn, queues)
+}
+
+func (c *Connection) Publish(queue string, msg Message) error {
+	return c.publisher.Publish(queue, amqp.Publishing{
+		Headers:       amqp.Table(msg.Headers),
+		ContentType:   msg.ContentType,
+		CorrelationId: msg.CorrelationId,
+		DeliveryMode:  amqp.Persistent,
+		Body:          msg.Body,
+	})
+}
+
+// Consume subscribes to a queue for as long as the connection is open,
+// re-subscribing whenever the channel or the connection is lost.
+func (c *Connection) Consume(consumer Consumer) {
+	go c.consume(consumer)
+}
+
+func (c *Connection) Status() Status {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	status := Status{Backend: "rabbitmq", State: c.state, Since: c.since}
+	if c.lastError != nil {
+		status.LastError = c.lastError.Error()
+	}
+	return status
+}
+
+func (c *Connection) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == StateClosed {
+		return nil
+	}
+	c.state = StateClosed
+	c.since = time.Now()
+	close(c.done)
+
+	if c.conn != nil {
+		return c.conn.Close()
+	}
+	return nil
+}
+
+// channel opens a channel on the current connection. It does not wait for a
+// reconnect, so publishers fail fast while the broker is unavailable.
+func (c *Connection) channel() (*amqp.Channel, error) {
+	c.mu.Lock()
+	conn, state := c.conn, c.state
+	c.mu.Unlock()
+
+	if state == StateClosed {
+		return nil, ErrClosed
+	}
+	if state != StateConnected || conn == nil {
+		return nil, ErrNotConnected
+	}
+	return conn.Channel()
+}
+
+func (c *Connection) supervise() {
+	delay := minReconnectDelay
+	for {
+		conn, err := c.connect()
+		if errors.Is(err, ErrQueueMismatch) {
+			c.setDisconnected(StateFailed, err)
+			log.Printf("RabbitMQ queues cannot be declared, giving up: %v", err)
+			return
+		}
+		if err != nil {
+			c.setDisconnected(StateConnecting, err)
+			log.Printf("RabbitMQ connection failed: %v. Retrying in %s...", err, delay)
+			if !c.sleep(delay) {
+				return
+			}
+			delay *= 2
+			if delay > maxReconnectDelay {
+				delay = maxReconnectDelay
+			}
+			continue
+		}
+		delay = minReconnectDelay
+
+		closed := conn.NotifyClose(make(chan *amqp.Error, 1))
+		log.Println("Successfully connected to RabbitMQ.")
+
+		select {
+		case amqpErr := <-closed:
+			err = ErrClosed
+			if amqpErr != nil {
+				err = amqpErr
+			}
+			c.setDisconnected(StateDisconnected, err)
+			log.Printf("RabbitMQ connection lost: %v. Reconnecting...", err)
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// connect dials, declares every queue registered so far and marks the
+// connection as established. Queues registered while it was declaring are
+// picked up before the connection is handed out.
+func (c *Connection) connect() (*amqp.Connection, error) {
+	conn, err := amqp.Dial(c.uri)
+	if err != nil {
+		return nil, err
+	}
+
+	declared := 0
+	for {
+		c.mu.Lock()
+		if c.state == StateClosed {
+			c.mu.Unlock()
+			conn.Close()
+			return nil, ErrClosed
+		}
+		pending := c.queues[declared:]
+		if len(pending) == 0 {
+			c.conn = conn
+			c.state = StateConnected
+			c.lastError = nil
+			c.since = time.Now()
+			close(c.ready)
+			c.mu.Unlock()
+			return conn, nil
+		}
+		c.mu.Unlock()
+
+		if err = declareOn(conn, pending); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to declare queues: %w", err)
+		}
+		declared += len(pending)
+	}
+}
+
+func declareOn(conn *amqp.Connection, queues []Queue) error {
+	channel, err := conn.Channel()
+	if err != nil {
+		return err
+	}
+	defer func() { channel.Close() }()
+
+	for _, queue := range queues {
+		err = declareQueue(channel, queue)
+		if isPreconditionFailed(err) {
+			// The broker closes the channel on a failed declaration.
+			if channel, err = conn.Channel(); err != nil {
+				return err
+			}
+			err = migrateQueue(channel, queue)
+		}
+		if err != nil {
+			log.Printf("Failed to declare RabbitMQ queue '%s': %v", queue.Name, err)
+			return err
+		}
+		log.Printf("Queue '%s' declared successfully.", queue.Name)
+	}
+	return nil
+}
+
+// migrateQueue replaces a queue that exists with other arguments, e.g. a task
+// queue declared before it dead-lettered to DeadLetterExchange or a retry
+// queue declared with another delay. RabbitMQ cannot change the arguments of
+// a queue, so it is deleted and declared again, but only while it is empty so
+// that no message is lost.
+func migrateQueue(channel *amqp.Channel, queue Queue) error {
+	_, err := channel.QueueDelete(queue.Name, false, true, false)
+	if isPreconditionFailed(err) {
+		return fmt.Errorf("%w: queue '%s' exists with other arguments and still holds messages; "+
+			"drain and delete it, or apply the new arguments with a RabbitMQ policy", ErrQueueMismatch, queue.Name)
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Queue '%s' was declared with other arguments and is empty, declaring it again", queue.Name)
+	return declareQueue(channel, queue)
+}
+
+func isPreconditionFailed(err error) bool {
+	var amqpErr *amqp.Error
+	return errors.As(err, &amqpErr) && amqpErr.Code == amqp.PreconditionFailed
+}
+
+func declareQueue(channel *amqp.Channel, queue Queue) error {
+	args := amqp.Table{}
+
+	if queue.DeadLetter {
+		err := channel.ExchangeDeclare(DeadLetterExchange, "direct", true, false, false, false, nil)
+		if err != nil {
+			return err
+		}
+
+		deadLetterQueue := DeadLetterQueue(queue.Name)
+		_, err = channel.QueueDeclare(deadLetterQueue, true, false, false, false, nil)
+		if err != nil {
+			return err
+		}
+		err = channel.QueueBind(deadLetterQueue, queue.Name, DeadLetterExchange, false, nil)
+		if err != nil {
+			return err
+		}
+
+		args["x-dead-letter-exchange"] = DeadLetterExchange
+	}
+
+	if queue.Delay > 0 {
+		args["x-message-ttl"] = queue.Delay.Milliseconds()
+		args["x-dead-letter-exchange"] = ""
+		args["x-dead-letter-routing-key"] = queue.DelayTarget
+	}
+
+	if len(args) == 0 {
+		args = nil
+	}
+
+	_, err := channel.QueueDeclare(
+		queue.Name, // queue name
+		true,       // durable
+		false,      // delete when unused
+		false,      // exclusive
+		false,      // no-wait
+		args,       // arguments
+	)
+	return err
+}
+
+func (c *Connection) setDisconnected(state State, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == StateClosed {
+		return
+	}
+	if c.state == StateConnected {
+		c.ready = make(chan struct{})
+		c.since = time.Now()
+	}
+	c.conn = nil
+	c.state = state
+	c.lastError = err
+}
+
+// waitConnected blocks until a connection is available and returns it, or
+// returns false once the Connection has been closed.
+func (c *Connection) waitConnected() (*amqp.Connection, bool) {
+	for {
+		c.mu.Lock()
+		conn, state, ready := c.conn, c.state, c.ready
+		c.mu.Unlock()
+
+		switch {
+		case state == StateClosed, state == StateFailed:
+			return nil, false
+		case state == StateConnected && !conn.IsClosed():
+			return conn, true
+		case state == StateConnected:
+			// The connection died but the supervisor has not noticed yet.
+			if !c.sleep(staleConnectionGap) {
+				return nil, false
+			}
+			continue
+		}
+
+		select {
+		case <-ready:
+		case <-c.done:
+			return nil, false
+		}
+	}
+}
+
+func (c *Connection) consume(consumer Consumer) {
+	for {
+		conn, ok := c.waitConnected()
+		if !ok {
+			return
+		}
+
+		channel, deliveries, err := subscribe(conn, consumer)
+		if err != nil {
+			log.Printf("Failed to consume from queue '%s': %v. Retrying in %s...", consumer.Queue, err, consumeRetryDelay)
+			if !c.sleep(consumeRetryDelay) {
+				return
+			}
+			continue
+		}
+
+		log.Printf("Consuming messages from queue '%s'", consumer.Queue)
+		for delivery := range deliveries {
+			consumer.Handler(newDelivery(consumer, delivery))
+		}
+		channel.Close()
+
+		log.Printf("Consumer for queue '%s' stopped, re-subscribing...", consumer.Queue)
+		if !c.sleep(minReconnectDelay) {
+			return
+		}
+	}
+}
+
+func newDelivery(consumer Consumer, delivery amqp.Delivery) Delivery {
+	d := Delivery{
+		Message: Message{
+			Body:          delivery.Body,
+			ContentType:   delivery.ContentType,
+			CorrelationId: delivery.CorrelationId,
+			Headers:       delivery.Headers,
+		},
+		Queue: consumer.Queue,
+	}
+	if !consumer.AutoAck {
+		d.ack = func() error { return delivery.Ack(false) }
+		d.nack = func(requeue bool) error { return delivery.Nack(false, requeue) }
+	}
+	return d
+}
+
+func subscribe(conn *amqp.Connection, consumer Consumer) (*amqp.Channel, <-chan amqp.Delivery, error) {
+	channel, err := conn.Channel()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if consumer.Prefetch > 0 {
+		if err = channel.Qos(consumer.Prefetch, 0, false); err != nil {
+			channel.Close()
+			return nil, nil, err
+		}
+	}
+
+	deliveries, err := channel.Consume(
+		consumer.Queue,
+		"",
+		consumer.AutoAck,
+		false,
+		false,
+		false,
+		nil,
+	)
+	if err != nil {
+		channel.Close()
+		return nil, nil, err
+	}
+	return channel, deliveries, nil
+}
+
+// sleep waits for d and reports false if the Connection was closed meanwhile.
+func (c *Connection) sleep(d time.Duration) bool {
+	select {
+	case <-time.After(d):
+		return true
+	case <-c.done:
+		return false
+	}
+}
@@ -0,0 +1,3 @@
+module mock-llm
+
+go 1.23.1
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"log"
+	"mock-llm/mock"
+	"net/http"
+	"os"
+)
+
+const defaultAddr = ":8090"
+
+func main() {
+	options, err := mock.OptionsFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	server, err := mock.NewServer(options)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	addr := os.Getenv("MOCK_LLM_ADDR")
+	if addr == "" {
+		addr = defaultAddr
+	}
+
+	log.Printf("Mock LLM server listening on %s (model %s, %d canned responses)", addr, options.Model, len(options.Responses))
+	if err = http.ListenAndServe(addr, server); err != nil {
+		log.Fatalf("Error starting server: %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+const defaultModel = "llama-3.1-8b-instant"
+
+// OptionsFromEnv reads the mock's options:
+//   - MOCK_LLM_MODEL: model reported when a request names none,
+//   - MOCK_LLM_RESPONSES: JSON file with a list of canned responses,
+//   - MOCK_LLM_LATENCY: delay of every response, e.g. "2s",
+//   - MOCK_LLM_FAIL_EVERY and MOCK_LLM_FAIL_STATUS: fail every n-th request
+//     with 429 (default) or 500,
+//   - MOCK_LLM_API_KEY: bearer token to require.
+func OptionsFromEnv() (Options, error) {
+	options := Options{
+		Model:      defaultModel,
+		FailStatus: http.StatusTooManyRequests,
+		APIKey:     os.Getenv("MOCK_LLM_API_KEY"),
+	}
+
+	if value := os.Getenv("MOCK_LLM_MODEL"); value != "" {
+		options.Model = value
+	}
+
+	if path := os.Getenv("MOCK_LLM_RESPONSES"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Options{}, fmt.Errorf("failed to read MOCK_LLM_RESPONSES: %w", err)
+		}
+		if err = json.Unmarshal(data, &options.Responses); err != nil {
+			return Options{}, fmt.Errorf("failed to parse MOCK_LLM_RESPONSES: %w", err)
+		}
+	}
+
+	if value := os.Getenv("MOCK_LLM_LATENCY"); value != "" {
+		latency, err := time.ParseDuration(value)
+		if err != nil {
+			return Options{}, fmt.Errorf("invalid MOCK_LLM_LATENCY %q: %w", value, err)
+		}
+		options.Latency = latency
+	}
+
+	if value := os.Getenv("MOCK_LLM_FAIL_EVERY"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return Options{}, fmt.Errorf("invalid MOCK_LLM_FAIL_EVERY %q", value)
+		}
+		options.FailEvery = n
+	}
+
+	if value := os.Getenv("MOCK_LLM_FAIL_STATUS"); value != "" {
+		status, err := strconv.Atoi(value)
+		if err != nil || (status != http.StatusTooManyRequests && status != http.StatusInternalServerError) {
+			return Options{}, fmt.Errorf("MOCK_LLM_FAIL_STATUS must be 429 or 500, got %q", value)
+		}
+		options.FailStatus = status
+	}
+
+	return options, nil
+}
@@ -0,0 +1,287 @@
+package mock
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"text/template"
+	"time"
+)
+
+// defaultTemplate is used when no canned response matches the prompt.
+const defaultTemplate = `Summary (mock response from {{.Model}}):
+The transcript has {{.Words}} words in {{.Lines}} lines. This text is generated by the offline mock LLM server and does not reflect the meeting content.
+
+Key points:
+- {{.FirstLine}}
+
+Decisions:
+- None recorded by the mock.`
+
+// Options control the mock's behaviour. Every request may override Latency
+// and the simulated status with the X-Mock-Latency and X-Mock-Status headers.
+type Options struct {
+	// Model is reported when a request does not name one.
+	Model     string
+	Responses []Response
+	// Latency delays every response.
+	Latency time.Duration
+	// FailEvery makes every n-th request fail with FailStatus (429 or 500).
+	FailEvery  int
+	FailStatus int
+	// APIKey, if set, is required as bearer token.
+	APIKey string
+}
+
+// Response is a canned reply. The first response whose Match occurs in the
+// last user message is used; an empty Match matches every prompt. Content is
+// a text/template executed with TemplateData.
+type Response struct {
+	Match   string `json:"match"`
+	Content string `json:"content"`
+}
+
+// TemplateData is available to response templates.
+type TemplateData struct {
+	Model     string
+	Prompt    string
+	FirstLine string
+	Words     int
+	Lines     int
+}
+
+type Server struct {
+	options   Options
+	templates []*template.Template
+	fallback  *template.Template
+	requests  atomic.Int64
+}
+
+// NewServer parses the response templates and returns the mock's handler.
+func NewServer(options Options) (*Server, error) {
+	s := &Server{options: options}
+	for i, response := range options.Responses {
+		tmpl, err := template.New(fmt.Sprintf("response-%d", i)).Parse(response.Content)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template for response %d: %w", i, err)
+		}
+		s.templates = append(s.templates, tmpl)
+	}
+	s.fallback = template.Must(template.New("default").Parse(defaultTemplate))
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/healthz":
+		w.Write([]byte("ok"))
+	case r.URL.Path == "/v1/models" && r.Method == http.MethodGet:
+		s.listModels(w)
+	case r.URL.Path == "/v1/chat/completions" && r.Method == http.MethodPost:
+		s.chatCompletions(w, r)
+	default:
+		writeError(w, http.StatusNotFound, "invalid_request_error", "unknown endpoint "+r.URL.Path)
+	}
+}
+
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	MaxTokens   int           `json:"max_tokens"`
+	Temperature float64       `json:"temperature"`
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatChoice struct {
+	Index        int         `json:"index"`
+	Message      chatMessage `json:"message"`
+	FinishReason string      `json:"finish_reason"`
+}
+
+type chatUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+type chatResponse struct {
+	Id      string       `json:"id"`
+	Object  string       `json:"object"`
+	Created int64        `json:"created"`
+	Model   string       `json:"model"`
+	Choices []chatChoice `json:"choices"`
+	Usage   chatUsage    `json:"usage"`
+}
+
+func (s *Server) chatCompletions(w http.ResponseWriter, r *http.Request) {
+	n := s.requests.Add(1)
+
+	if s.options.APIKey != "" && r.Header.Get("Authorization") != "Bearer "+s.options.APIKey {
+		writeError(w, http.StatusUnauthorized, "invalid_request_error", "invalid API key")
+		return
+	}
+
+	var req chatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "invalid JSON body")
+		return
+	}
+	if len(req.Messages) == 0 {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "messages must not be empty")
+		return
+	}
+
+	latency := s.options.Latency
+	if value := r.Header.Get("X-Mock-Latency"); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			latency = parsed
+		}
+	}
+	time.Sleep(latency)
+
+	status := http.StatusOK
+	if s.options.FailEvery > 0 && n%int64(s.options.FailEvery) == 0 {
+		status = s.options.FailStatus
+	}
+	if value := r.Header.Get("X-Mock-Status"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			status = parsed
+		}
+	}
+
+	switch status {
+	case http.StatusOK:
+	case http.StatusTooManyRequests:
+		w.Header().Set("Retry-After", "1")
+		writeError(w, status, "rate_limit_error", "rate limit reached (simulated)")
+		return
+	default:
+		writeError(w, status, "server_error", "internal error (simulated)")
+		return
+	}
+
+	model := req.Model
+	if model == "" {
+		model = s.options.Model
+	}
+
+	prompt := lastUserMessage(req.Messages)
+	content, err := s.render(TemplateData{
+		Model:     model,
+		Prompt:    prompt,
+		FirstLine: firstLine(prompt),
+		Words:     len(strings.Fields(prompt)),
+		Lines:     strings.Count(strings.TrimSpace(prompt), "\n") + 1,
+	})
+	if err != nil {
+		log.Printf("Failed to render mock response: %v", err)
+		writeError(w, http.StatusInternalServerError, "server_error", err.Error())
+		return
+	}
+
+	promptTokens := countTokens(req.Messages)
+	completionTokens := len(strings.Fields(content))
+
+	response := chatResponse{
+		Id:      "chatcmpl-mock-" + requestHash(req),
+		Object:  "chat.completion",
+		Created: 0,
+		Model:   model,
+		Choices: []chatChoice{{
+			Message:      chatMessage{Role: "assistant", Content: content},
+			FinishReason: "stop",
+		}},
+		Usage: chatUsage{
+			PromptTokens:     promptTokens,
+			CompletionTokens: completionTokens,
+			TotalTokens:      promptTokens + completionTokens,
+		},
+	}
+
+	log.Printf("Mock completion for model %s: %d prompt words, status %d", model, promptTokens, status)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (s *Server) render(data TemplateData) (string, error) {
+	tmpl := s.fallback
+	for i, response := range s.options.Responses {
+		if strings.Contains(data.Prompt, response.Match) {
+			tmpl = s.templates[i]
+			break
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func (s *Server) listModels(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"object": "list",
+		"data": []map[string]interface{}{
+			{"id": s.options.Model, "object": "model", "owned_by": "mock"},
+		},
+	})
+}
+
+func writeError(w http.ResponseWriter, status int, errorType, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]interface{}{
+			"message": message,
+			"type":    errorType,
+			"code":    status,
+		},
+	})
+}
+
+func lastUserMessage(messages []chatMessage) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			return messages[i].Content
+		}
+	}
+	return messages[len(messages)-1].Content
+}
+
+func firstLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// countTokens approximates tokens by words, which is enough for a mock.
+func countTokens(messages []chatMessage) int {
+	tokens := 0
+	for _, message := range messages {
+		tokens += len(strings.Fields(message.Content))
+	}
+	return tokens
+}
+
+// requestHash keeps response IDs stable for identical requests.
+func requestHash(req chatRequest) string {
+	data, _ := json.Marshal(req)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
@@ -1,4 +1,6 @@
 import logging
+import os
+import socket
 import pika
 import time
 import json
@@ -62,6 +64,8 @@ def process_message(ch, method, properties, body, ack_channel):
 
 def send_ack_message(message, ack_channel):
     try:
+        message.setdefault("schema_version", 1)
+        message.setdefault("worker_id", socket.gethostname())
         ack_channel.queue_declare(queue='orchestrator_ack_queue', durable=True)
 
         ack_channel.basic_publish(
@@ -85,7 +89,7 @@ def main():
         try:
             logging.info("Connecting to RabbitMQ...")
             connection = pika.BlockingConnection(pika.ConnectionParameters(
-                host='rabbitmq',
+                host=os.environ.get('RABBITMQ_HOST', 'rabbitmq'),
                 port=5672,
                 credentials=credentials,
                 connection_attempts=5,
@@ -97,7 +101,11 @@ def main():
             ack_channel = connection.channel()
             logging.info("Connected to RabbitMQ")
 
-            channel.queue_declare(queue='ocr_queue', durable=True)
+            channel.queue_declare(
+                queue='ocr_queue',
+                durable=True,
+                arguments={'x-dead-letter-exchange': 'tasks.dlx'}
+            )
             logging.info("Queue declared.")
 
             channel.basic_qos(prefetch_count=1)
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"log"
+	"orchestrator-service/queues"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultAckPrefetch    = 32
+	defaultAckWorkers     = 8
+	defaultAckMaxAttempts = 5
+	ackRetryDelay         = time.Second
+)
+
+// AckConsumerOptions returns the prefetch and worker pool size of the ack
+// consumer, read from ACK_PREFETCH and ACK_WORKERS, and how often a failing
+// ack is retried before it is parked, read from ACK_MAX_ATTEMPTS.
+func AckConsumerOptions() queues.PoolOptions {
+	return queues.PoolOptions{
+		Prefetch: positiveInt("ACK_PREFETCH", defaultAckPrefetch),
+		Workers:  positiveInt("ACK_WORKERS", defaultAckWorkers),
+		Retry: queues.RetryPolicy{
+			MaxAttempts:  positiveInt("ACK_MAX_ATTEMPTS", defaultAckMaxAttempts),
+			InitialDelay: ackRetryDelay,
+		},
+	}
+}
+
+func positiveInt(name string, fallback int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		log.Printf("Invalid %s %q, using default %d", name, value, fallback)
+		return fallback
+	}
+	return n
+}
@@ -3,11 +3,21 @@ package config
 import (
 	"fmt"
 	"github.com/go-redis/redis/v8"
+	"os"
 )
 
-func ConnectToRedis() (*redis.Client, error) {
-	addr := "redis:6379"
+const defaultRedisAddr = "redis:6379"
 
+// RedisAddr returns REDIS_ADDR, or the compose service address if it is not
+// set.
+func RedisAddr() string {
+	if addr := os.Getenv("REDIS_ADDR"); addr != "" {
+		return addr
+	}
+	return defaultRedisAddr
+}
+
+func ConnectToRedis(addr string) (*redis.Client, error) {
 	client := redis.NewClient(&redis.Options{
 		Addr: addr,
 	})
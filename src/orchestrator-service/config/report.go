@@ -0,0 +1,26 @@
+package config
+
+import (
+	"log"
+	"os"
+	"time"
+)
+
+const defaultReportRetention = 24 * time.Hour
+
+// ReportRetention returns how long a generated report stays downloadable,
+// read from REPORT_RETENTION (e.g. "72h").
+func ReportRetention() time.Duration {
+	value := os.Getenv("REPORT_RETENTION")
+	if value == "" {
+		return defaultReportRetention
+	}
+
+	retention, err := time.ParseDuration(value)
+	if err != nil || retention <= 0 {
+		log.Printf("Invalid REPORT_RETENTION %q, using default %s", value, defaultReportRetention)
+		return defaultReportRetention
+	}
+
+	return retention
+}
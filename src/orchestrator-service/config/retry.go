@@ -0,0 +1,34 @@
+package config
+
+import (
+	"log"
+	"orchestrator-service/queues"
+	"os"
+	"time"
+)
+
+const (
+	defaultTaskMaxAttempts = 4
+	defaultTaskRetryDelay  = 10 * time.Second
+)
+
+// TaskRetryPolicy returns how often a task rejected by a worker is retried,
+// read from TASK_MAX_ATTEMPTS and TASK_RETRY_DELAY (the first backoff delay,
+// e.g. "30s"; later retries double it).
+func TaskRetryPolicy() queues.RetryPolicy {
+	policy := queues.RetryPolicy{
+		MaxAttempts:  positiveInt("TASK_MAX_ATTEMPTS", defaultTaskMaxAttempts),
+		InitialDelay: defaultTaskRetryDelay,
+	}
+
+	if value := os.Getenv("TASK_RETRY_DELAY"); value != "" {
+		delay, err := time.ParseDuration(value)
+		if err != nil || delay <= 0 {
+			log.Printf("Invalid TASK_RETRY_DELAY %q, using default %s", value, defaultTaskRetryDelay)
+		} else {
+			policy.InitialDelay = delay
+		}
+	}
+
+	return policy
+}
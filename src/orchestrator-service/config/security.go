@@ -0,0 +1,45 @@
+package config
+
+import (
+	"crypto/rand"
+	"log"
+	"os"
+	"strings"
+)
+
+var defaultAllowedOrigins = []string{"http://localhost:3000", "http://127.0.0.1:3000"}
+
+// MeetingTokenSecret returns the key used to sign meeting tokens, read from
+// MEETING_TOKEN_SECRET. Without it a random key is generated, which means
+// tokens stop being valid when the orchestrator restarts.
+func MeetingTokenSecret() []byte {
+	secret := os.Getenv("MEETING_TOKEN_SECRET")
+	if secret != "" {
+		return []byte(secret)
+	}
+
+	log.Println("MEETING_TOKEN_SECRET is not set, generating a random secret.")
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		log.Fatalf("Failed to generate meeting token secret: %v", err)
+	}
+	return buf
+}
+
+// AllowedOrigins returns the CORS origin allowlist, read from the
+// comma-separated CORS_ALLOWED_ORIGINS.
+func AllowedOrigins() []string {
+	value := os.Getenv("CORS_ALLOWED_ORIGINS")
+	if value == "" {
+		return defaultAllowedOrigins
+	}
+
+	var origins []string
+	for _, origin := range strings.Split(value, ",") {
+		origin = strings.TrimSpace(origin)
+		if origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
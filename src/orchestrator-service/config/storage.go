@@ -0,0 +1,27 @@
+package config
+
+import (
+	"context"
+	"log"
+	"storage"
+)
+
+func DeleteMeetingData(ctx context.Context, store storage.Store, databaseName, meetingID string) error {
+	collections := []string{"summaries", "statistics", "ocr_results", "transcriptions", "embeddings", "meetings"}
+
+	for _, collectionName := range collections {
+		collection := store.Collection(databaseName, collectionName)
+
+		filter := storage.Filter{"meeting_id": meetingID}
+
+		deleted, err := collection.DeleteMany(ctx, filter)
+		if err != nil {
+			log.Printf("Error deleting documents from collection %s: %v", collectionName, err)
+			return err
+		}
+
+		log.Printf("Deleted %d documents from collection %s with meeting_id=%s", deleted, collectionName, meetingID)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package events
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+const subscriberBuffer = 32
+
+type Event struct {
+	MeetingId string `json:"meeting_id"`
+	Type      string `json:"type"`
+	TaskId    string `json:"task_id,omitempty"`
+	TaskType  string `json:"task_type,omitempty"`
+	Status    string `json:"status,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Timestamp string `json:"timestamp"`
+}
+
+type Broker struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[chan Event]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		subscribers: make(map[string]map[chan Event]struct{}),
+	}
+}
+
+func (b *Broker) Subscribe(meetingID string) chan Event {
+	ch := make(chan Event, subscriberBuffer)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subscribers[meetingID] == nil {
+		b.subscribers[meetingID] = make(map[chan Event]struct{})
+	}
+	b.subscribers[meetingID][ch] = struct{}{}
+
+	return ch
+}
+
+func (b *Broker) Unsubscribe(meetingID string, ch chan Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subs, ok := b.subscribers[meetingID]
+	if !ok {
+		return
+	}
+	if _, ok = subs[ch]; !ok {
+		return
+	}
+
+	delete(subs, ch)
+	close(ch)
+	if len(subs) == 0 {
+		delete(b.subscribers, meetingID)
+	}
+}
+
+// Publish never blocks: a subscriber that cannot keep up misses the event.
+func (b *Broker) Publish(event Event) {
+	if b == nil {
+		return
+	}
+	if event.Timestamp == "" {
+		event.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers[event.MeetingId] {
+		select {
+		case ch <- event:
+		default:
+			log.Printf("Dropping event %s for slow subscriber of meeting_id=%s", event.Type, event.MeetingId)
+		}
+	}
+}
@@ -2,109 +2,79 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
+	"contracts"
+	"errors"
 	"fmt"
-	"github.com/streadway/amqp"
+	goredis "github.com/go-redis/redis/v8"
 	"log"
-	"orchestrator-service/config"
+	"messaging"
+	"orchestrator-service/events"
+	"orchestrator-service/queues"
 	"orchestrator-service/redis"
 	"os"
 	"path/filepath"
 )
 
-type AckMessage struct {
-	MeetingId string `json:"meeting_id"`
-	TaskId    string `json:"task_id"`
-	TaskType  string `json:"task_type"`
-	Status    string `json:"status"`
+// AckMeetingKey routes acknowledgements of the same meeting to the same
+// worker so they are applied in the order they were sent.
+func AckMeetingKey(msg messaging.Delivery) string {
+	ack, err := contracts.DecodeAckMessage(msg.Body)
+	if err != nil {
+		return ""
+	}
+	return ack.MeetingId
 }
 
-func HandleAckMessage(rm *redis.RedisManager, taskHandler *TaskHandler, appConfig *Config) func(amqp.Delivery) {
-	return func(msg amqp.Delivery) {
-		var ack AckMessage
-		if err := json.Unmarshal(msg.Body, &ack); err != nil {
-			log.Printf("Failed to unmarshal ACK message: %v", err)
-			return
+// HandleAckMessage returns nil once an acknowledgement has been fully applied.
+// Errors wrapping queues.ErrDiscard drop the message; any other error
+// is retried and eventually parked.
+func HandleAckMessage(rm *redis.RedisManager, appConfig *Config) func(messaging.Delivery) error {
+	return func(msg messaging.Delivery) error {
+		ack, err := contracts.DecodeAckMessage(msg.Body)
+		if err != nil {
+			return fmt.Errorf("%w: %v", queues.ErrDiscard, err)
+		}
+
+		if !isValidMeetingId(ack.MeetingId) {
+			return fmt.Errorf("%w: invalid meeting_id in ACK %+v", queues.ErrDiscard, ack)
 		}
 
 		log.Printf("Processing ACK: %+v", ack)
 
 		ctx := context.Background()
-		err := rm.UpdateTaskStatus(ctx, ack.MeetingId, ack.TaskId, ack.Status)
+		err = rm.UpdateTask(ctx, ack.MeetingId, ack.TaskId, redis.TaskUpdate{
+			Status:    ack.Status,
+			WorkerId:  ack.WorkerId,
+			LastError: ack.Error,
+		})
+		if errors.Is(err, redis.ErrTaskNotFound) {
+			return fmt.Errorf("%w: %v", queues.ErrDiscard, err)
+		}
 		if err != nil {
-			log.Printf("Failed to update Redis for ACK: %v", err)
-			return
+			return fmt.Errorf("failed to update Redis for ACK: %w", err)
 		}
 
+		appConfig.Events.Publish(events.Event{
+			MeetingId: ack.MeetingId,
+			Type:      fmt.Sprintf("%s_%s", ack.TaskType, ack.Status),
+			TaskId:    ack.TaskId,
+			TaskType:  ack.TaskType,
+			Status:    ack.Status,
+		})
+
 		meetingStatus, err := rm.GetMeetingStatus(ctx, ack.MeetingId)
-		if err != nil || meetingStatus != "ended" {
+		if errors.Is(err, goredis.Nil) || (err == nil && meetingStatus != "ended") {
 			log.Printf("Meeting %s is not ended yet", ack.MeetingId)
-			return
+			return nil
 		}
-
-		if ack.TaskType == "transcription" && ack.Status == "completed" {
-			allTranscriptionCompleted, err := rm.AllTasksOfTypeCompleted(ctx, ack.MeetingId, ack.TaskType)
-			if err != nil {
-				log.Printf("Failed to check if all tasks are completed for meeting_id: %s %v", ack.MeetingId, err)
-				return
-			}
-
-			if allTranscriptionCompleted {
-				log.Printf("All tasks completed for meeting_id: %s", ack.MeetingId)
-				err = taskHandler.SendSummaryTask(ack.MeetingId)
-				if err != nil {
-					log.Printf("Error sending summary task for meeting_id: %s %v", ack.MeetingId, err)
-				}
-			}
-		}
-
-		if ack.TaskType == "ocr" || ack.TaskType == "summary" {
-			err = checkAndSendReport(ctx, rm, taskHandler, ack.MeetingId)
-			if err != nil {
-				log.Printf("Error sending report task for meeting_id: %s %v", ack.MeetingId, err)
-			}
+		if err != nil {
+			return fmt.Errorf("failed to get meeting status: %w", err)
 		}
 
-		if ack.TaskType == "report" && ack.Status == "completed" {
-			log.Printf("All tasks completed for meeting_id: %s", ack.MeetingId)
-			email, err := rm.GetMeetingEmail(ctx, ack.MeetingId)
-			if err != nil {
-				log.Printf("Failed to get email for meeting_id: %s %v", ack.MeetingId, err)
-				return
-			}
-			filePath := fmt.Sprintf("/shared-report/%s/meeting_report_%s.pdf", ack.MeetingId, ack.MeetingId)
-			err = taskHandler.SendEmailTask(ack.MeetingId, filePath, email)
-			if err != nil {
-				log.Printf("Error sending email task for meeting_id: %s %v", ack.MeetingId, err)
-			}
-
-		}
-
-		if ack.TaskType == "email" && ack.Status == "completed" {
-			log.Printf("Mail sent successfully for meeting_id: %s, deleting entries", ack.MeetingId)
-			err = rm.DeleteAllMeetingEntries(ctx, ack.MeetingId)
-			if err != nil {
-				log.Printf("Failed to delete all meeting redis entries for meeting_id: %s %v", ack.MeetingId, err)
-			}
-
-			err = config.DeleteMeetingData(ctx, appConfig.MongoClient, "database", ack.MeetingId)
-			if err != nil {
-				log.Printf("Failed to delete all meeting data from mongo for meeting_id: %s %v", ack.MeetingId, err)
-			}
-
-			volumes := []string{
-				"/shared-transcription",
-				"/shared-ocr",
-				"/shared-report",
-			}
-
-			err = DeleteMeetingDirectories(ack.MeetingId, volumes)
-			if err != nil {
-				log.Printf("Failed to delete meeting directories for meeting_id: %s %v", ack.MeetingId, err)
-			}
-
-			log.Printf("Successfully deleted all meeting data for meeting_id: %s", ack.MeetingId)
+		if err = appConfig.AdvancePipeline(ctx, ack.MeetingId); err != nil {
+			return fmt.Errorf("failed to advance pipeline for meeting_id: %s %w", ack.MeetingId, err)
 		}
+		return nil
 	}
 }
 
@@ -123,27 +93,3 @@ func DeleteMeetingDirectories(meetingID string, volumes []string) error {
 
 	return nil
 }
-
-func checkAndSendReport(ctx context.Context, rm *redis.RedisManager, taskHandler *TaskHandler, meetingID string) error {
-	allOCRCompleted, err := rm.AllTasksOfTypeCompleted(ctx, meetingID, "ocr")
-	if err != nil {
-		return fmt.Errorf("failed to check OCR tasks for meeting_id: %s %v", meetingID, err)
-	}
-
-	allSummaryCompleted, err := rm.AllTasksOfTypeCompleted(ctx, meetingID, "summary")
-	if err != nil {
-		return fmt.Errorf("failed to check Summary tasks for meeting_id: %s %v", meetingID, err)
-	}
-
-	allTranscriptionCompleted, err := rm.AllTasksOfTypeCompleted(ctx, meetingID, "transcription")
-	if err != nil {
-		return fmt.Errorf("failed to check Transcription tasks for meeting_id: %s %v", meetingID, err)
-	}
-
-	if allOCRCompleted && allSummaryCompleted && allTranscriptionCompleted {
-		log.Printf("All OCR and Summary tasks completed for meeting_id: %s. Sending report task...", meetingID)
-		return taskHandler.SendReportTask(meetingID)
-	}
-
-	return nil
-}
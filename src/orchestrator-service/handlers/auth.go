@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	goredis "github.com/go-redis/redis/v8"
+)
+
+// signMeetingToken returns the capability token for a meeting. Tokens are
+// an HMAC of the meeting ID, so they can be verified without extra state.
+func (app *Config) signMeetingToken(meetingId string) string {
+	mac := hmac.New(sha256.New, app.TokenSecret)
+	mac.Write([]byte(meetingId))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (app *Config) validMeetingToken(meetingId, token string) bool {
+	expected := app.signMeetingToken(meetingId)
+	return hmac.Equal([]byte(token), []byte(expected))
+}
+
+// meetingTokenFromRequest accepts the token as a bearer token, an
+// X-Meeting-Token header or, for EventSource clients that cannot set
+// headers, a token query parameter.
+func meetingTokenFromRequest(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	if token := r.Header.Get("X-Meeting-Token"); token != "" {
+		return token
+	}
+	return r.URL.Query().Get("token")
+}
+
+// authorizeMeeting checks the meeting ID format, the meeting token and that
+// the meeting exists.
+// With requireActive set, meetings that have already ended are rejected.
+// It writes the error response itself and reports whether to continue.
+func (app *Config) authorizeMeeting(w http.ResponseWriter, r *http.Request, meetingId string, requireActive bool) bool {
+	if !isValidMeetingId(meetingId) {
+		http.Error(w, "Invalid meeting_id", http.StatusBadRequest)
+		return false
+	}
+
+	token := meetingTokenFromRequest(r)
+	if token == "" {
+		http.Error(w, "Missing meeting token", http.StatusUnauthorized)
+		return false
+	}
+
+	if !app.validMeetingToken(meetingId, token) {
+		http.Error(w, "Invalid meeting token", http.StatusForbidden)
+		return false
+	}
+
+	status, err := app.RedisManager.GetMeetingStatus(context.Background(), meetingId)
+	if errors.Is(err, goredis.Nil) {
+		http.Error(w, "Meeting not found", http.StatusNotFound)
+		return false
+	}
+	if err != nil {
+		http.Error(w, "Failed to get meeting status", http.StatusInternalServerError)
+		log.Printf("Failed to get meeting status for meeting_id=%s: %v", meetingId, err)
+		return false
+	}
+
+	if requireActive && status != "started" {
+		http.Error(w, "Meeting already ended", http.StatusConflict)
+		return false
+	}
+
+	return true
+}
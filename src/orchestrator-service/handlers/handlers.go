@@ -2,32 +2,49 @@ package handlers
 
 import (
 	"context"
+	"contracts"
 	"encoding/json"
 	"fmt"
-	"github.com/streadway/amqp"
-	"go.mongodb.org/mongo-driver/mongo"
+	"github.com/go-chi/chi/v5"
 	"io"
 	"log"
 	"math/rand"
+	"messaging"
 	"net/http"
+	"orchestrator-service/events"
+	"orchestrator-service/pipeline"
 	"orchestrator-service/redis"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"storage"
 	"strings"
 	"time"
 )
 
 type MeetingIdResponse struct {
-	MeetingId string `json:"meeting_id"`
+	MeetingId   string `json:"meeting_id"`
+	Token       string `json:"token"`
+	ReportToken string `json:"report_token"`
+}
+
+type MeetingStatusResponse struct {
+	MeetingId string           `json:"meeting_id"`
+	Status    string           `json:"status"`
+	Tasks     []redis.TaskInfo `json:"tasks"`
 }
 
 type Config struct {
-	MongoClient   *mongo.Client
-	RabbitChannel *amqp.Channel
-	RedisManager  *redis.RedisManager
-	TaskHandler   *TaskHandler
+	Store           storage.Store
+	Broker          messaging.Broker
+	RedisManager    *redis.RedisManager
+	TaskHandler     *TaskHandler
+	Events          *events.Broker
+	Workflow        *pipeline.Workflow
+	ReportRetention time.Duration
+	TokenSecret     []byte
+	AllowedOrigins  []string
 }
 
 func isValidEmail(email string) bool {
@@ -35,6 +52,20 @@ func isValidEmail(email string) bool {
 	return re.MatchString(email)
 }
 
+func newMeetingId() string {
+	src := rand.NewSource(time.Now().UnixNano())
+	rng := rand.New(src)
+
+	const idLength = 10
+	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+	meetingId := make([]byte, idLength)
+	for i := range meetingId {
+		meetingId[i] = charset[rng.Intn(len(charset))]
+	}
+	return string(meetingId)
+}
+
 func (app *Config) GenerateMeetingId(w http.ResponseWriter, r *http.Request) {
 
 	email := r.URL.Query().Get("email")
@@ -48,30 +79,38 @@ func (app *Config) GenerateMeetingId(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	src := rand.NewSource(time.Now().UnixNano())
-	rng := rand.New(src)
+	meetingId := newMeetingId()
 
-	const idLength = 10
-	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-
-	meetingId := make([]byte, idLength)
-	for i := range meetingId {
-		meetingId[i] = charset[rng.Intn(len(charset))]
+	reportToken, err := generateReportToken()
+	if err != nil {
+		http.Error(w, "Failed to generate report token", http.StatusInternalServerError)
+		log.Printf("Failed to generate report token: %v", err)
+		return
 	}
 
 	response := MeetingIdResponse{
-		MeetingId: string(meetingId),
+		MeetingId:   meetingId,
+		Token:       app.signMeetingToken(meetingId),
+		ReportToken: reportToken,
 	}
 
 	ctx := context.Background()
 	if err := app.RedisManager.SetMeetingStatus(ctx, response.MeetingId, "started"); err != nil {
 		http.Error(w, "Failed to set meeting status", http.StatusInternalServerError)
 		log.Printf("Failed to set meeting status: %v", err)
+		return
 	}
 
 	if err := app.RedisManager.SetMeetingEmail(ctx, response.MeetingId, email); err != nil {
 		http.Error(w, "Failed to set meeting email", http.StatusInternalServerError)
 		log.Printf("Failed to set meeting email: %v", err)
+		return
+	}
+
+	if err := app.RedisManager.SetReportToken(ctx, response.MeetingId, reportToken); err != nil {
+		http.Error(w, "Failed to set report token", http.StatusInternalServerError)
+		log.Printf("Failed to set report token: %v", err)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -103,6 +142,10 @@ func (app *Config) EndMeeting(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !app.authorizeMeeting(w, r, meetingId, true) {
+		return
+	}
+
 	ctx := context.Background()
 	if err = app.RedisManager.SetMeetingStatus(ctx, meetingId, "ended"); err != nil {
 		http.Error(w, "Failed to set meeting status", http.StatusInternalServerError)
@@ -110,26 +153,146 @@ func (app *Config) EndMeeting(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	app.CheckDependenciesAndTriggerTasks(meetingId)
+	app.Events.Publish(events.Event{MeetingId: meetingId, Type: "meeting_ended"})
+
+	if err = app.AdvancePipeline(ctx, meetingId); err != nil {
+		log.Printf("Failed to advance pipeline for meeting_id=%s: %v", meetingId, err)
+	}
 
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte(`{"message": "Meeting ended successfully"}`))
 }
 
+func (app *Config) GetMeetingStatus(w http.ResponseWriter, r *http.Request) {
+	meetingId := chi.URLParam(r, "id")
+	if meetingId == "" {
+		http.Error(w, "Missing meeting id", http.StatusBadRequest)
+		return
+	}
+
+	if !app.authorizeMeeting(w, r, meetingId, false) {
+		return
+	}
+
+	response, err := app.meetingStatus(context.Background(), meetingId)
+	if err != nil {
+		http.Error(w, "Failed to get meeting status", http.StatusInternalServerError)
+		log.Printf("Failed to get meeting status for meeting_id=%s: %v", meetingId, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (app *Config) meetingStatus(ctx context.Context, meetingId string) (MeetingStatusResponse, error) {
+	status, err := app.RedisManager.GetMeetingStatus(ctx, meetingId)
+	if err != nil {
+		return MeetingStatusResponse{}, err
+	}
+
+	tasks, err := app.RedisManager.GetTasks(ctx, meetingId)
+	if err != nil {
+		return MeetingStatusResponse{}, fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	return MeetingStatusResponse{
+		MeetingId: meetingId,
+		Status:    status,
+		Tasks:     tasks,
+	}, nil
+}
+
+// StreamMeetingEvents sends the events of a meeting as server-sent events.
+// The first event, "snapshot", carries the current status as returned by
+// GetMeetingStatus, so clients do not miss what happened before they
+// subscribed.
+func (app *Config) StreamMeetingEvents(w http.ResponseWriter, r *http.Request) {
+	meetingId := chi.URLParam(r, "id")
+	if meetingId == "" {
+		http.Error(w, "Missing meeting id", http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	if !app.authorizeMeeting(w, r, meetingId, false) {
+		return
+	}
+
+	sub := app.Events.Subscribe(meetingId)
+	defer app.Events.Unsubscribe(meetingId, sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	// Subscribed first, so events published while the snapshot is read are
+	// delivered after it rather than lost.
+	snapshot, err := app.meetingStatus(r.Context(), meetingId)
+	if err != nil {
+		log.Printf("Failed to get meeting status for meeting_id=%s: %v", meetingId, err)
+		return
+	}
+	body, err := json.Marshal(snapshot)
+	if err != nil {
+		log.Printf("Failed to marshal status for meeting_id=%s: %v", meetingId, err)
+		return
+	}
+	fmt.Fprintf(w, "event: snapshot\ndata: %s\n\n", body)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(15 * time.Second)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		case event, ok := <-sub:
+			if !ok {
+				return
+			}
+			body, err := json.Marshal(event)
+			if err != nil {
+				log.Printf("Failed to marshal event for meeting_id=%s: %v", meetingId, err)
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, body)
+			flusher.Flush()
+		}
+	}
+}
+
 func (app *Config) CaptureScreenshots(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
 		return
 	}
 
+	r.Body = http.MaxBytesReader(w, r.Body, maxScreenshotSize)
+
 	err := r.ParseMultipartForm(50 << 20)
+	if isRequestTooLarge(err) {
+		http.Error(w, fmt.Sprintf("Screenshot exceeds the %d MB limit", maxScreenshotSize>>20), http.StatusRequestEntityTooLarge)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Unable to parse form", http.StatusBadRequest)
 		log.Printf("Error parsing form: %v", err)
 		return
 	}
 
-	file, header, err := r.FormFile("screenshot")
+	file, _, err := r.FormFile("screenshot")
 	if err != nil {
 		http.Error(w, "Missing screenshot file", http.StatusBadRequest)
 		log.Printf("Error retrieving file: %v", err)
@@ -143,7 +306,18 @@ func (app *Config) CaptureScreenshots(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	basePath := "/shared-ocr"
+	if !app.authorizeMeeting(w, r, meetingId, true) {
+		return
+	}
+
+	ext, err := sniffContentType(file, imageTypes)
+	if err != nil {
+		http.Error(w, "Unsupported screenshot format", http.StatusUnsupportedMediaType)
+		log.Printf("Rejected screenshot for meeting_id=%s: %v", meetingId, err)
+		return
+	}
+
+	basePath := contracts.SharedDir(contracts.OCRDir)
 	meetingDir := filepath.Join(basePath, meetingId)
 
 	err = os.MkdirAll(meetingDir, os.ModePerm)
@@ -153,7 +327,7 @@ func (app *Config) CaptureScreenshots(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	filePath := filepath.Join(meetingDir, header.Filename)
+	filePath := filepath.Join(meetingDir, fmt.Sprintf("screenshot_%d%s", time.Now().UnixNano(), ext))
 	out, err := os.Create(filePath)
 	if err != nil {
 		http.Error(w, "Unable to save file", http.StatusInternalServerError)
@@ -170,6 +344,7 @@ func (app *Config) CaptureScreenshots(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log.Printf("Screenshot saved successfully in: %s", filePath)
+	app.Events.Publish(events.Event{MeetingId: meetingId, Type: "screenshot_received"})
 
 	err = app.TaskHandler.SendOcrTask(meetingId, filePath)
 	if err != nil {
@@ -186,14 +361,20 @@ func (app *Config) CaptureAudio(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	r.Body = http.MaxBytesReader(w, r.Body, maxAudioSize)
+
 	err := r.ParseMultipartForm(100 << 20)
+	if isRequestTooLarge(err) {
+		http.Error(w, fmt.Sprintf("Audio exceeds the %d MB limit", maxAudioSize>>20), http.StatusRequestEntityTooLarge)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Unable to parse form", http.StatusBadRequest)
 		log.Printf("Error parsing form: %v", err)
 		return
 	}
 
-	file, header, err := r.FormFile("audio")
+	file, _, err := r.FormFile("audio")
 	if err != nil {
 		http.Error(w, "Missing audio file", http.StatusBadRequest)
 		log.Printf("Error retrieving file: %v", err)
@@ -207,7 +388,18 @@ func (app *Config) CaptureAudio(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	basePath := "/shared-transcription"
+	if !app.authorizeMeeting(w, r, meetingId, true) {
+		return
+	}
+
+	ext, err := sniffContentType(file, audioTypes)
+	if err != nil {
+		http.Error(w, "Unsupported audio format", http.StatusUnsupportedMediaType)
+		log.Printf("Rejected audio for meeting_id=%s: %v", meetingId, err)
+		return
+	}
+
+	basePath := contracts.SharedDir(contracts.TranscriptionDir)
 	meetingDir := filepath.Join(basePath, meetingId)
 
 	err = os.MkdirAll(meetingDir, os.ModePerm)
@@ -217,7 +409,7 @@ func (app *Config) CaptureAudio(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	webmFilePath := filepath.Join(meetingDir, header.Filename)
+	webmFilePath := filepath.Join(meetingDir, fmt.Sprintf("audio_%d%s", time.Now().UnixNano(), ext))
 	webmFile, err := os.Create(webmFilePath)
 	if err != nil {
 		http.Error(w, "Unable to save file", http.StatusInternalServerError)
@@ -233,51 +425,47 @@ func (app *Config) CaptureAudio(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	log.Printf("WebM audio saved successfully in: %s", webmFilePath)
+	log.Printf("Audio chunk saved successfully in: %s", webmFilePath)
+	app.Events.Publish(events.Event{MeetingId: meetingId, Type: "audio_received"})
 
-	wavFilePath := filepath.Join(meetingDir, strings.TrimSuffix(header.Filename, ".webm")+".wav")
-
-	err = convertWebmToWav(webmFilePath, wavFilePath)
+	err = app.dispatchAudio(meetingId, webmFilePath)
 	if err != nil {
-		http.Error(w, "Error converting audio to WAV", http.StatusInternalServerError)
-		log.Printf("Error converting audio: %v", err)
+		http.Error(w, "Error processing audio", http.StatusInternalServerError)
+		log.Printf("Error processing audio: %v", err)
 		return
 	}
 
-	log.Printf("Audio converted to WAV successfully: %s", wavFilePath)
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Audio uploaded successfully"))
+}
 
-	err = os.Remove(webmFilePath)
+// dispatchAudio converts a saved audio chunk to WAV, sends a transcription
+// task for the result and removes the original. On error the original is
+// kept, so the chunk can be dispatched again.
+func (app *Config) dispatchAudio(meetingId, webmFilePath string) error {
+	wavFilePath := strings.TrimSuffix(webmFilePath, filepath.Ext(webmFilePath)) + ".wav"
+
+	err := convertWebmToWav(webmFilePath, wavFilePath)
 	if err != nil {
-		log.Printf("Error deleting original WebM file: %v", err)
+		os.Remove(wavFilePath)
+		return err
 	}
 
-	log.Printf("Audio saved successfully in: %s", wavFilePath)
+	log.Printf("Audio converted to WAV successfully: %s", wavFilePath)
 
 	err = app.TaskHandler.SendTranscriptionTask(meetingId, wavFilePath)
 	if err != nil {
-		log.Printf("Error sending transcription task: %v", err)
+		os.Remove(wavFilePath)
+		return fmt.Errorf("failed to send transcription task: %w", err)
 	}
 
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("Audio uploaded successfully"))
-}
-
-func (app *Config) CheckDependenciesAndTriggerTasks(meetingId string) {
-	ctx := context.Background()
-
-	allTranscriptionCompleted, err := app.RedisManager.AllTasksOfTypeCompleted(ctx, meetingId, "transcription")
+	err = os.Remove(webmFilePath)
 	if err != nil {
-		log.Printf("Failed to check transcription tasks for meeting_id=%s: %v", meetingId, err)
-		return
+		log.Printf("Error deleting original WebM file: %v", err)
 	}
 
-	if allTranscriptionCompleted {
-		log.Printf("All transcription tasks completed for meeting_id=%s. Sending summary task...", meetingId)
-		err = app.TaskHandler.SendSummaryTask(meetingId)
-		if err != nil {
-			log.Printf("Error sending summary task for meeting_id=%s: %v", meetingId, err)
-		}
-	}
+	log.Printf("Audio saved successfully in: %s", wavFilePath)
+	return nil
 }
 
 func convertWebmToWav(inputPath, outputPath string) error {
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"context"
+	"contracts"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"orchestrator-service/events"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	maxImportSize        = 4 << 30
+	importSegmentSeconds = 20
+	importFrameSeconds   = 10
+)
+
+// ImportMeeting accepts a complete pre-recorded meeting, splits it into
+// audio chunks and video frames and feeds them through the regular
+// transcription and OCR pipeline as if they had been captured live.
+func (app *Config) ImportMeeting(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	err := r.ParseMultipartForm(32 << 20)
+	if isRequestTooLarge(err) {
+		http.Error(w, fmt.Sprintf("Recording exceeds the %d GB limit", maxImportSize>>30), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Unable to parse form", http.StatusBadRequest)
+		log.Printf("Error parsing form: %v", err)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	meetingId := r.FormValue("meeting_id")
+	if meetingId == "" {
+		http.Error(w, "Missing meeting_id", http.StatusBadRequest)
+		return
+	}
+
+	if !app.authorizeMeeting(w, r, meetingId, true) {
+		return
+	}
+
+	file, _, err := r.FormFile("recording")
+	if err != nil {
+		http.Error(w, "Missing recording file", http.StatusBadRequest)
+		log.Printf("Error retrieving file: %v", err)
+		return
+	}
+	defer file.Close()
+
+	ext, err := sniffContentType(file, recordingTypes)
+	if err != nil {
+		http.Error(w, "Unsupported recording format, expected mp4, mkv, webm or mp3", http.StatusUnsupportedMediaType)
+		log.Printf("Rejected recording for meeting_id=%s: %v", meetingId, err)
+		return
+	}
+
+	importDir, err := os.MkdirTemp("", "import-"+meetingId+"-")
+	if err != nil {
+		http.Error(w, "Unable to create directory", http.StatusInternalServerError)
+		log.Printf("Error creating directory: %v", err)
+		return
+	}
+
+	recordingPath := filepath.Join(importDir, "recording"+ext)
+	out, err := os.Create(recordingPath)
+	if err != nil {
+		os.RemoveAll(importDir)
+		http.Error(w, "Unable to save file", http.StatusInternalServerError)
+		log.Printf("Error saving file: %v", err)
+		return
+	}
+
+	_, err = io.Copy(out, file)
+	out.Close()
+	if err != nil {
+		os.RemoveAll(importDir)
+		http.Error(w, "Error saving file", http.StatusInternalServerError)
+		log.Printf("Error writing file: %v", err)
+		return
+	}
+
+	log.Printf("Recording for meeting_id=%s saved in: %s", meetingId, recordingPath)
+	app.Events.Publish(events.Event{MeetingId: meetingId, Type: "recording_received"})
+
+	go func() {
+		defer os.RemoveAll(importDir)
+		if err := app.processImportedRecording(meetingId, recordingPath); err != nil {
+			log.Printf("Error importing recording for meeting_id=%s: %v", meetingId, err)
+			app.failImport(meetingId, err)
+		}
+	}()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	w.Write([]byte(`{"message": "Recording accepted for processing"}`))
+}
+
+func (app *Config) processImportedRecording(meetingId, recordingPath string) error {
+	audioDir := filepath.Join(contracts.SharedDir(contracts.TranscriptionDir), meetingId)
+	if err := os.MkdirAll(audioDir, os.ModePerm); err != nil {
+		return fmt.Errorf("error creating directory: %w", err)
+	}
+
+	audioChunks, err := segmentAudio(recordingPath, audioDir)
+	if err != nil {
+		return err
+	}
+	log.Printf("Split recording for meeting_id=%s into %d audio chunks", meetingId, len(audioChunks))
+
+	hasVideo, err := hasVideoStream(recordingPath)
+	if err != nil {
+		return err
+	}
+
+	var frames []string
+	if hasVideo {
+		frameDir := filepath.Join(contracts.SharedDir(contracts.OCRDir), meetingId)
+		if err = os.MkdirAll(frameDir, os.ModePerm); err != nil {
+			return fmt.Errorf("error creating directory: %w", err)
+		}
+
+		frames, err = extractFrames(recordingPath, frameDir)
+		if err != nil {
+			return err
+		}
+		log.Printf("Extracted %d frames from recording for meeting_id=%s", len(frames), meetingId)
+	}
+
+	// A meeting missing some of its chunks would still produce a report that
+	// looks complete, so the import stops at the first task that cannot be
+	// sent.
+	for _, chunk := range audioChunks {
+		if err = app.TaskHandler.SendTranscriptionTask(meetingId, chunk); err != nil {
+			return fmt.Errorf("failed to send transcription task: %w", err)
+		}
+	}
+
+	for _, frame := range frames {
+		if err = app.TaskHandler.SendOcrTask(meetingId, frame); err != nil {
+			return fmt.Errorf("failed to send OCR task: %w", err)
+		}
+	}
+
+	ctx := context.Background()
+	if err = app.RedisManager.SetMeetingStatus(ctx, meetingId, "ended"); err != nil {
+		return fmt.Errorf("failed to set meeting status: %w", err)
+	}
+
+	return app.AdvancePipeline(ctx, meetingId)
+}
+
+// failImport marks a meeting whose recording could not be imported as failed,
+// which stops its pipeline, and tells the client why.
+func (app *Config) failImport(meetingId string, importErr error) {
+	if err := app.RedisManager.SetMeetingStatus(context.Background(), meetingId, "failed"); err != nil {
+		log.Printf("Failed to set meeting status for meeting_id=%s: %v", meetingId, err)
+	}
+
+	app.Events.Publish(events.Event{
+		MeetingId: meetingId,
+		Type:      "import_failed",
+		Status:    "failed",
+		Error:     importErr.Error(),
+	})
+}
+
+func segmentAudio(inputPath, outputDir string) ([]string, error) {
+	prefix := fmt.Sprintf("import_audio_%d_", time.Now().UnixNano())
+	pattern := filepath.Join(outputDir, prefix+"%04d.wav")
+
+	cmd := exec.Command("ffmpeg", "-i", inputPath, "-vn", "-acodec", "pcm_s16le", "-ar", "44100", "-ac", "2",
+		"-f", "segment", "-segment_time", fmt.Sprint(importSegmentSeconds), "-reset_timestamps", "1", pattern)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg error: %v, output: %s", err, string(output))
+	}
+
+	return globSorted(filepath.Join(outputDir, prefix+"*.wav"))
+}
+
+func extractFrames(inputPath, outputDir string) ([]string, error) {
+	prefix := fmt.Sprintf("import_frame_%d_", time.Now().UnixNano())
+	pattern := filepath.Join(outputDir, prefix+"%04d.png")
+
+	cmd := exec.Command("ffmpeg", "-i", inputPath, "-an", "-vf", fmt.Sprintf("fps=1/%d", importFrameSeconds), pattern)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg error: %v, output: %s", err, string(output))
+	}
+
+	return globSorted(filepath.Join(outputDir, prefix+"*.png"))
+}
+
+func hasVideoStream(inputPath string) (bool, error) {
+	cmd := exec.Command("ffprobe", "-v", "error", "-select_streams", "v", "-show_entries", "stream=index", "-of", "csv=p=0", inputPath)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return false, fmt.Errorf("ffprobe error: %v, output: %s", err, string(output))
+	}
+
+	return strings.TrimSpace(string(output)) != "", nil
+}
+
+func globSorted(pattern string) ([]string, error) {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
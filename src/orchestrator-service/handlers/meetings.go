@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"contracts"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type CreateMeetingRequest struct {
+	Title        string   `json:"title"`
+	Agenda       string   `json:"agenda"`
+	Language     string   `json:"language"`
+	Participants []string `json:"participants"`
+	Recipients   []string `json:"recipients"`
+	// Summary optionally overrides the LLM provider, model and sampling
+	// parameters the summary service uses for this meeting.
+	Summary *contracts.SummarySettings `json:"summary"`
+}
+
+type MeetingRecord struct {
+	MeetingId    string    `bson:"meeting_id" json:"meeting_id"`
+	Title        string    `bson:"title" json:"title"`
+	Agenda       string    `bson:"agenda" json:"agenda"`
+	Language     string    `bson:"language" json:"language"`
+	Participants []string  `bson:"participants" json:"participants"`
+	Recipients   []string  `bson:"recipients" json:"recipients"`
+	CreatedAt    time.Time `bson:"created_at" json:"created_at"`
+
+	Summary *contracts.SummarySettings `bson:"summary,omitempty" json:"summary,omitempty"`
+}
+
+func (app *Config) CreateMeeting(w http.ResponseWriter, r *http.Request) {
+	var req CreateMeetingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		log.Printf("Error decoding JSON: %v", err)
+		return
+	}
+
+	req.Title = strings.TrimSpace(req.Title)
+	if req.Title == "" {
+		http.Error(w, "Missing title in request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Recipients) == 0 {
+		http.Error(w, "Missing recipients in request body", http.StatusBadRequest)
+		return
+	}
+
+	for _, recipient := range req.Recipients {
+		if !isValidEmail(recipient) {
+			http.Error(w, "Invalid email format: "+recipient, http.StatusBadRequest)
+			return
+		}
+	}
+
+	if req.Summary != nil {
+		if err := req.Summary.Validate(); err != nil {
+			http.Error(w, "Invalid summary settings: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	reportToken, err := generateReportToken()
+	if err != nil {
+		http.Error(w, "Failed to generate report token", http.StatusInternalServerError)
+		log.Printf("Failed to generate report token: %v", err)
+		return
+	}
+
+	meeting := MeetingRecord{
+		MeetingId:    newMeetingId(),
+		Title:        req.Title,
+		Agenda:       req.Agenda,
+		Language:     req.Language,
+		Participants: req.Participants,
+		Recipients:   req.Recipients,
+		CreatedAt:    time.Now().UTC(),
+		Summary:      req.Summary,
+	}
+
+	ctx := context.Background()
+	collection := app.Store.Collection("database", "meetings")
+	if err = collection.InsertOne(ctx, meeting); err != nil {
+		http.Error(w, "Failed to save meeting", http.StatusInternalServerError)
+		log.Printf("Failed to save meeting record: %v", err)
+		return
+	}
+
+	if err = app.RedisManager.SetMeetingStatus(ctx, meeting.MeetingId, "started"); err != nil {
+		http.Error(w, "Failed to set meeting status", http.StatusInternalServerError)
+		log.Printf("Failed to set meeting status: %v", err)
+		return
+	}
+
+	if err = app.RedisManager.SetMeetingRecipients(ctx, meeting.MeetingId, meeting.Recipients); err != nil {
+		http.Error(w, "Failed to set meeting recipients", http.StatusInternalServerError)
+		log.Printf("Failed to set meeting recipients: %v", err)
+		return
+	}
+
+	if err = app.RedisManager.SetReportToken(ctx, meeting.MeetingId, reportToken); err != nil {
+		http.Error(w, "Failed to set report token", http.StatusInternalServerError)
+		log.Printf("Failed to set report token: %v", err)
+		return
+	}
+
+	log.Printf("Created meeting %s (%q) with %d recipients", meeting.MeetingId, meeting.Title, len(meeting.Recipients))
+
+	response := MeetingIdResponse{
+		MeetingId:   meeting.MeetingId,
+		Token:       app.signMeetingToken(meeting.MeetingId),
+		ReportToken: reportToken,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
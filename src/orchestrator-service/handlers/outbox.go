@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"messaging"
+	"orchestrator-service/events"
+	"orchestrator-service/redis"
+	"time"
+)
+
+const (
+	outboxBatchSize  = 100
+	outboxRetryDelay = 5 * time.Second
+)
+
+// RunOutboxRelay publishes the tasks recorded in the outbox. It runs a pass
+// every interval and whenever a new task is queued, and removes a task from
+// the outbox only after the broker has confirmed it. A task that cannot be
+// published stays in the outbox and is retried after outboxRetryDelay.
+func (h *TaskHandler) RunOutboxRelay(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-h.outboxWake:
+		}
+		h.relayOutbox(context.Background())
+	}
+}
+
+func (h *TaskHandler) wakeOutboxRelay() {
+	select {
+	case h.outboxWake <- struct{}{}:
+	default:
+	}
+}
+
+func (h *TaskHandler) relayOutbox(ctx context.Context) {
+	taskIDs, err := h.RedisManager.DueOutboxTasks(ctx, time.Now(), outboxBatchSize)
+	if err != nil {
+		log.Printf("Outbox relay failed to read due tasks: %v", err)
+		return
+	}
+
+	for _, taskID := range taskIDs {
+		if !h.relayTask(ctx, taskID) {
+			return
+		}
+	}
+}
+
+// relayTask publishes one outbox entry and reports whether the relay should
+// continue with the next one.
+func (h *TaskHandler) relayTask(ctx context.Context, taskID string) bool {
+	task, err := h.RedisManager.GetTask(ctx, taskID)
+	if errors.Is(err, redis.ErrTaskNotFound) {
+		// The meeting was cleaned up before the task went out.
+		if err = h.RedisManager.DropOutboxTask(ctx, taskID); err != nil {
+			log.Printf("Outbox relay failed to drop task %s: %v", taskID, err)
+		}
+		return true
+	}
+	if err != nil {
+		log.Printf("Outbox relay failed to read task %s: %v", taskID, err)
+		return false
+	}
+
+	// Tasks recorded before their queue was stored go to their stage's queue.
+	if task.Queue == "" {
+		if stage, ok := h.Workflow.Stage(task.TaskType); ok {
+			task.Queue = stage.Queue
+		}
+	}
+
+	if task.Queue == "" {
+		err = fmt.Errorf("%w: no queue for task type %s", messaging.ErrUnroutable, task.TaskType)
+	} else {
+		err = h.Broker.Publish(
+			task.Queue,
+			messaging.Message{
+				ContentType:   "application/json",
+				Body:          task.Payload,
+				CorrelationId: task.TaskId,
+			},
+		)
+	}
+	switch {
+	case errors.Is(err, messaging.ErrUnroutable):
+		// No queue will ever take this task, so retrying is pointless.
+		log.Printf("Task %s is unroutable to %s: %v", task.TaskId, task.Queue, err)
+		if dropErr := h.RedisManager.DropOutboxTask(ctx, task.TaskId); dropErr != nil {
+			log.Printf("Outbox relay failed to drop task %s: %v", task.TaskId, dropErr)
+		}
+		h.FailTask(ctx, task, err.Error())
+		return true
+	case err != nil:
+		log.Printf("Failed to publish task %s to %s, retrying in %s: %v", task.TaskId, task.Queue, outboxRetryDelay, err)
+		h.recordPublishFailure(ctx, task.MeetingId, task.TaskId, "pending", err)
+		if err = h.RedisManager.DeferOutboxTask(ctx, task.TaskId, time.Now().Add(outboxRetryDelay)); err != nil {
+			log.Printf("Outbox relay failed to defer task %s: %v", task.TaskId, err)
+		}
+		// The broker is likely unavailable; leave the rest for the next pass.
+		return false
+	}
+
+	if err = h.RedisManager.MarkTaskSent(ctx, task.TaskId); err != nil {
+		// The task stays in the outbox and is published again, which workers
+		// tolerate because acks are keyed by task ID.
+		log.Printf("Outbox relay failed to mark task %s as sent: %v", task.TaskId, err)
+		return false
+	}
+
+	log.Printf("Task sent to %s: %s", task.Queue, task.Payload)
+	h.Events.Publish(events.Event{
+		MeetingId: task.MeetingId,
+		Type:      task.TaskType + "_dispatched",
+		TaskId:    task.TaskId,
+		TaskType:  task.TaskType,
+		Status:    "pending",
+	})
+	return true
+}
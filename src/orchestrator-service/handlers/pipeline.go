@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"contracts"
+	"errors"
+	"fmt"
+	"log"
+	"orchestrator-service/config"
+	"orchestrator-service/pipeline"
+)
+
+// cleanupStage is the claim key that makes sure a finished meeting is only
+// cleaned up once. Workflow stages cannot start with an underscore, so it
+// never collides with one.
+const cleanupStage = "_cleanup"
+
+// stagePayloads fills in the task fields a stage needs beyond its
+// identifiers. Stages without an entry only receive meeting_id and task_id.
+var stagePayloads = map[string]func(ctx context.Context, app *Config, task *contracts.TaskMessage) error{
+	"email": emailPayload,
+}
+
+// emailPayload sends one task covering every recipient. The email field keeps
+// the first recipient for workers that only understand a single address.
+func emailPayload(ctx context.Context, app *Config, task *contracts.TaskMessage) error {
+	recipients, err := app.RedisManager.GetMeetingRecipients(ctx, task.MeetingId)
+	if err != nil {
+		return fmt.Errorf("failed to get recipients: %w", err)
+	}
+	if len(recipients) == 0 {
+		return fmt.Errorf("no recipients for meeting_id: %s", task.MeetingId)
+	}
+
+	task.FilePath = reportFilePath(task.MeetingId)
+	task.Email = recipients[0]
+	task.Emails = recipients
+	return nil
+}
+
+func (app *Config) pipelineState(ctx context.Context, meetingId string) (map[string]pipeline.StageState, error) {
+	tasks, err := app.RedisManager.GetTasks(ctx, meetingId)
+	if err != nil {
+		return nil, err
+	}
+
+	state := make(map[string]pipeline.StageState)
+	for _, task := range tasks {
+		stageState := state[task.TaskType]
+		stageState.Total++
+		if task.Status == "completed" {
+			stageState.Completed++
+		}
+		state[task.TaskType] = stageState
+	}
+	return state, nil
+}
+
+// AdvancePipeline dispatches every stage of an ended meeting whose
+// dependencies are done, and cleans the meeting up once the whole workflow
+// has finished.
+func (app *Config) AdvancePipeline(ctx context.Context, meetingId string) error {
+	state, err := app.pipelineState(ctx, meetingId)
+	if err != nil {
+		return fmt.Errorf("failed to read tasks: %w", err)
+	}
+
+	if app.Workflow.Finished(state) {
+		claimed, err := app.RedisManager.ClaimStage(ctx, meetingId, cleanupStage)
+		if err != nil {
+			return fmt.Errorf("failed to claim cleanup: %w", err)
+		}
+		if !claimed {
+			return nil
+		}
+
+		log.Printf("All stages completed for meeting_id: %s, deleting entries", meetingId)
+		app.cleanupMeeting(ctx, meetingId)
+		return nil
+	}
+
+	// A stage is claimed in the same Redis write that records its task, so a
+	// stage that fails to dispatch is left unclaimed and the error returned;
+	// the acknowledgement is retried and dispatches it again.
+	var errs []error
+	for _, stage := range app.Workflow.ReadyStages(state) {
+		if err = app.dispatchStage(ctx, stage, meetingId); err != nil {
+			log.Printf("Failed to dispatch %s stage for meeting_id: %s %v", stage.Name, meetingId, err)
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (app *Config) dispatchStage(ctx context.Context, stage pipeline.Stage, meetingId string) error {
+	task := contracts.NewTaskMessage(meetingId, stage.Name)
+	if build, ok := stagePayloads[stage.Name]; ok {
+		if err := build(ctx, app, &task); err != nil {
+			return fmt.Errorf("failed to build %s task: %w", stage.Name, err)
+		}
+	}
+
+	dispatched, err := app.TaskHandler.DispatchStage(stage, task)
+	if err != nil {
+		return fmt.Errorf("failed to send %s task: %w", stage.Name, err)
+	}
+	if !dispatched {
+		log.Printf("Stage %s already dispatched for meeting_id: %s", stage.Name, meetingId)
+		return nil
+	}
+
+	log.Printf("Dependencies of %s completed for meeting_id: %s. Sent %s task", stage.Name, meetingId, stage.Name)
+	return nil
+}
+
+func (app *Config) cleanupMeeting(ctx context.Context, meetingId string) {
+	err := app.RedisManager.ExpireReportToken(ctx, meetingId, app.ReportRetention)
+	if err != nil {
+		log.Printf("Failed to set report token expiry for meeting_id: %s %v", meetingId, err)
+	}
+
+	err = app.RedisManager.DeleteAllMeetingEntries(ctx, meetingId)
+	if err != nil {
+		log.Printf("Failed to delete all meeting redis entries for meeting_id: %s %v", meetingId, err)
+	}
+
+	err = config.DeleteMeetingData(ctx, app.Store, "database", meetingId)
+	if err != nil {
+		log.Printf("Failed to delete all meeting data from storage for meeting_id: %s %v", meetingId, err)
+	}
+
+	// The report itself stays downloadable until CleanupExpiredReports removes it.
+	volumes := []string{
+		contracts.SharedDir(contracts.TranscriptionDir),
+		contracts.SharedDir(contracts.OCRDir),
+	}
+
+	err = DeleteMeetingDirectories(meetingId, volumes)
+	if err != nil {
+		log.Printf("Failed to delete meeting directories for meeting_id: %s %v", meetingId, err)
+	}
+
+	log.Printf("Successfully deleted all meeting data for meeting_id: %s", meetingId)
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"context"
+	"contracts"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	goredis "github.com/go-redis/redis/v8"
+)
+
+func reportBasePath() string {
+	return contracts.SharedDir(contracts.ReportDir)
+}
+
+func reportFilePath(meetingId string) string {
+	return filepath.Join(reportBasePath(), meetingId, fmt.Sprintf("meeting_report_%s.pdf", meetingId))
+}
+
+func generateReportToken() (string, error) {
+	return randomHex(32)
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func (app *Config) DownloadReport(w http.ResponseWriter, r *http.Request) {
+	meetingId := chi.URLParam(r, "id")
+	if !isValidMeetingId(meetingId) {
+		http.Error(w, "Invalid meeting id", http.StatusBadRequest)
+		return
+	}
+
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		token = r.Header.Get("X-Report-Token")
+	}
+	if token == "" {
+		http.Error(w, "Missing report token", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := context.Background()
+	expected, err := app.RedisManager.GetReportToken(ctx, meetingId)
+	if errors.Is(err, goredis.Nil) {
+		http.Error(w, "Report not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to verify report token", http.StatusInternalServerError)
+		log.Printf("Failed to get report token for meeting_id=%s: %v", meetingId, err)
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+		http.Error(w, "Invalid report token", http.StatusForbidden)
+		return
+	}
+
+	filePath := reportFilePath(meetingId)
+	file, err := os.Open(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		http.Error(w, "Report not ready", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to open report", http.StatusInternalServerError)
+		log.Printf("Failed to open report %s: %v", filePath, err)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		http.Error(w, "Failed to open report", http.StatusInternalServerError)
+		log.Printf("Failed to stat report %s: %v", filePath, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filepath.Base(filePath)))
+	http.ServeContent(w, r, filepath.Base(filePath), info.ModTime(), file)
+}
+
+// CleanupExpiredReports periodically removes report directories that are
+// older than the retention window.
+func CleanupExpiredReports(retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		entries, err := os.ReadDir(reportBasePath())
+		if err != nil {
+			log.Printf("Failed to read report directory %s: %v", reportBasePath(), err)
+			continue
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+
+			info, err := entry.Info()
+			if err != nil {
+				log.Printf("Failed to stat report directory %s: %v", entry.Name(), err)
+				continue
+			}
+
+			if time.Since(info.ModTime()) < retention {
+				continue
+			}
+
+			err = DeleteMeetingDirectories(entry.Name(), []string{reportBasePath()})
+			if err != nil {
+				log.Printf("Failed to delete expired report for meeting_id: %s %v", entry.Name(), err)
+			}
+		}
+	}
+}
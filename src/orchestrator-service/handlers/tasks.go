@@ -2,190 +2,181 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
+	"contracts"
 	"fmt"
 	"log"
+	"messaging"
+	"orchestrator-service/events"
+	"orchestrator-service/pipeline"
 	"orchestrator-service/redis"
 	"time"
-
-	"github.com/streadway/amqp"
 )
 
 type TaskHandler struct {
-	RabbitChannel *amqp.Channel
-	RedisManager  *redis.RedisManager
-}
+	Broker       messaging.Broker
+	RedisManager *redis.RedisManager
+	Events       *events.Broker
+	Workflow     *pipeline.Workflow
 
-type LogMessage struct {
-	Timestamp string                 `json:"timestamp"`
-	Service   string                 `json:"service"`
-	Level     string                 `json:"level"`
-	Message   string                 `json:"message"`
-	Details   map[string]interface{} `json:"details"`
+	outboxWake chan struct{}
 }
 
-func (h *TaskHandler) SendEmailTask(meetingId, filePath, email string) error {
-	taskID := fmt.Sprintf("%s-email-%d", meetingId, time.Now().UnixNano())
-	taskMessage := fmt.Sprintf(`{"meeting_id": "%s", "task_id": "%s", "file_path": "%s", "email": "%s"}`, meetingId, taskID, filePath, email)
-
-	ctx := context.Background()
-	err := h.RedisManager.AddTask(ctx, meetingId, taskID)
-	if err != nil {
-		return fmt.Errorf("failed to add task to Redis: %w", err)
+func NewTaskHandler(messageBroker messaging.Broker, redisManager *redis.RedisManager, eventBroker *events.Broker, workflow *pipeline.Workflow) *TaskHandler {
+	return &TaskHandler{
+		Broker:       messageBroker,
+		RedisManager: redisManager,
+		Events:       eventBroker,
+		Workflow:     workflow,
+		outboxWake:   make(chan struct{}, 1),
 	}
+}
 
-	err = h.RabbitChannel.Publish(
-		"",
-		"email_queue",
-		false,
-		false,
-		amqp.Publishing{
-			ContentType:   "application/json",
-			Body:          []byte(taskMessage),
-			CorrelationId: taskID,
-		},
-	)
-	if err != nil {
-		h.RedisManager.UpdateTaskStatus(ctx, meetingId, taskID, "failed")
-		return fmt.Errorf("failed to publish task to RabbitMQ: %w", err)
-	}
+// DispatchStage claims a pipeline stage for the task's meeting and sends the
+// task to the stage's queue. It reports false, sending nothing, if the stage
+// was already dispatched.
+func (h *TaskHandler) DispatchStage(stage pipeline.Stage, task contracts.TaskMessage) (bool, error) {
+	return h.queueTask(stage.Queue, task, true)
+}
 
-	log.Printf("Task sent to email_queue: %s", taskMessage)
-	return nil
+func (h *TaskHandler) SendTranscriptionTask(meetingId, filePath string) error {
+	return h.sendSourceTask("transcription", meetingId, filePath)
 }
 
-func (h *TaskHandler) SendReportTask(meetingId string) error {
-	taskID := fmt.Sprintf("%s-report-%d", meetingId, time.Now().UnixNano())
-	taskMessage := fmt.Sprintf(`{"meeting_id": "%s", "task_id": "%s"}`, meetingId, taskID)
+func (h *TaskHandler) SendOcrTask(meetingId, filePath string) error {
+	return h.sendSourceTask("ocr", meetingId, filePath)
+}
 
-	ctx := context.Background()
-	err := h.RedisManager.AddTask(ctx, meetingId, taskID)
-	if err != nil {
-		return fmt.Errorf("failed to add task to Redis: %w", err)
+// sendSourceTask sends an uploaded file to the queue the workflow gives its
+// source stage.
+func (h *TaskHandler) sendSourceTask(stageName, meetingId, filePath string) error {
+	stage, ok := h.Workflow.Stage(stageName)
+	if !ok || !stage.Source {
+		return fmt.Errorf("workflow has no %s source stage", stageName)
 	}
 
-	err = h.RabbitChannel.Publish(
-		"",
-		"report_queue",
-		false,
-		false,
-		amqp.Publishing{
-			ContentType:   "application/json",
-			Body:          []byte(taskMessage),
-			CorrelationId: taskID,
-		},
-	)
+	task := contracts.NewTaskMessage(meetingId, stage.Name)
+	task.FilePath = filePath
+	return h.sendTask(stage.Queue, task)
+}
+
+// RedispatchTask queues the stored message of an existing task again,
+// keeping its task ID so a late ack from the first attempt still counts.
+func (h *TaskHandler) RedispatchTask(meetingId, taskID string) error {
+	attempts, err := h.RedisManager.RequeueTask(context.Background(), taskID)
 	if err != nil {
-		h.RedisManager.UpdateTaskStatus(ctx, meetingId, taskID, "failed")
-		return fmt.Errorf("failed to publish task to RabbitMQ: %w", err)
+		return fmt.Errorf("failed to requeue task in Redis: %w", err)
 	}
 
-	log.Printf("Task sent to report_queue: %s", taskMessage)
+	log.Printf("Task %s for meeting_id=%s queued for attempt %d", taskID, meetingId, attempts)
+	h.wakeOutboxRelay()
 	return nil
 }
 
-func (h *TaskHandler) SendSummaryTask(meetingId string) error {
-	taskID := fmt.Sprintf("%s-summary-%d", meetingId, time.Now().UnixNano())
-	taskMessage := fmt.Sprintf(`{"meeting_id": "%s", "task_id": "%s"}`, meetingId, taskID)
+// sendTask records the task and its outbox entry; the outbox relay publishes
+// it. An error means nothing was recorded and nothing will be published.
+func (h *TaskHandler) sendTask(queue string, task contracts.TaskMessage) error {
+	_, err := h.queueTask(queue, task, false)
+	return err
+}
 
-	ctx := context.Background()
-	err := h.RedisManager.AddTask(ctx, meetingId, taskID)
+// queueTask is sendTask that optionally claims the task's stage in the same
+// Redis write, so a claimed stage always has its task.
+func (h *TaskHandler) queueTask(queue string, task contracts.TaskMessage, claimStage bool) (bool, error) {
+	meetingId, taskType := task.MeetingId, task.TaskType
+	taskID := fmt.Sprintf("%s-%s-%d", meetingId, taskType, time.Now().UnixNano())
+	task.TaskId = taskID
+
+	body, err := contracts.Encode(&task)
 	if err != nil {
-		return fmt.Errorf("failed to add task to Redis: %w", err)
+		return false, fmt.Errorf("failed to encode %s task: %w", taskType, err)
 	}
 
-	err = h.RabbitChannel.Publish(
-		"",
-		"summary_queue",
-		false,
-		false,
-		amqp.Publishing{
-			ContentType:   "application/json",
-			Body:          []byte(taskMessage),
-			CorrelationId: taskID,
-		},
-	)
+	ctx := context.Background()
+	added := true
+	if claimStage {
+		added, err = h.RedisManager.AddStageTask(ctx, meetingId, taskID, taskType, queue, string(body))
+	} else {
+		err = h.RedisManager.AddTask(ctx, meetingId, taskID, taskType, queue, string(body))
+	}
 	if err != nil {
-		h.RedisManager.UpdateTaskStatus(ctx, meetingId, taskID, "failed")
-		return fmt.Errorf("failed to publish task to RabbitMQ: %w", err)
+		return false, fmt.Errorf("failed to add task to Redis: %w", err)
+	}
+	if !added {
+		return false, nil
 	}
 
-	log.Printf("Task sent to summary_queue: %s", taskMessage)
-	return nil
+	log.Printf("Task %s queued for %s", taskID, queue)
+	h.wakeOutboxRelay()
+	return true, nil
 }
 
-func (h *TaskHandler) SendTranscriptionTask(meetingId, filePath string) error {
-	taskID := fmt.Sprintf("%s-transcription-%d", meetingId, time.Now().UnixNano())
-	taskMessage := fmt.Sprintf(`{"meeting_id": "%s", "file_path": "%s", "task_id": "%s"}`, meetingId, filePath, taskID)
-
-	ctx := context.Background()
-	err := h.RedisManager.AddTask(ctx, meetingId, taskID)
+// FailTask marks a task that will not be retried any more as failed. The
+// stages depending on it can never run, so the meeting's pipeline is marked
+// failed as well, which stops it, and clients are told so.
+func (h *TaskHandler) FailTask(ctx context.Context, task redis.TaskInfo, reason string) {
+	err := h.RedisManager.UpdateTask(ctx, task.MeetingId, task.TaskId, redis.TaskUpdate{
+		Status:    "failed",
+		LastError: reason,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to add task to Redis: %w", err)
+		log.Printf("Failed to mark task %s as failed: %v", task.TaskId, err)
 	}
 
-	err = h.RabbitChannel.Publish(
-		"",
-		"transcription_queue",
-		false,
-		false,
-		amqp.Publishing{
-			ContentType:   "application/json",
-			Body:          []byte(taskMessage),
-			CorrelationId: taskID,
-		},
-	)
-	if err != nil {
-		h.RedisManager.UpdateTaskStatus(ctx, meetingId, taskID, "failed")
-		return fmt.Errorf("failed to publish task to RabbitMQ: %w", err)
+	h.Events.Publish(events.Event{
+		MeetingId: task.MeetingId,
+		Type:      task.TaskType + "_failed",
+		TaskId:    task.TaskId,
+		TaskType:  task.TaskType,
+		Status:    "failed",
+	})
+
+	if err = h.RedisManager.SetMeetingStatus(ctx, task.MeetingId, "failed"); err != nil {
+		log.Printf("Failed to set meeting status for meeting_id=%s: %v", task.MeetingId, err)
 	}
 
-	log.Printf("Task sent to transcription_queue: %s", taskMessage)
-	return nil
+	h.Events.Publish(events.Event{
+		MeetingId: task.MeetingId,
+		Type:      "pipeline_failed",
+		TaskId:    task.TaskId,
+		TaskType:  task.TaskType,
+		Status:    "failed",
+		Error:     reason,
+	})
 }
 
-func (h *TaskHandler) SendOcrTask(meetingId, filePath string) error {
-	taskID := fmt.Sprintf("%s-ocr-%d", meetingId, time.Now().UnixNano())
-	taskMessage := fmt.Sprintf(`{"meeting_id": "%s", "file_path": "%s", "task_id": "%s"}`, meetingId, filePath, taskID)
-
+// TaskParked is called once a task has been rejected by workers too often
+// and was moved to its parking queue.
+func (h *TaskHandler) TaskParked(taskID string, attempts int) {
 	ctx := context.Background()
-	err := h.RedisManager.AddTask(ctx, meetingId, taskID)
+	task, err := h.RedisManager.GetTask(ctx, taskID)
 	if err != nil {
-		return fmt.Errorf("failed to add task to Redis: %w", err)
+		log.Printf("Failed to read parked task %s: %v", taskID, err)
+		return
 	}
+	h.FailTask(ctx, task, fmt.Sprintf("rejected by workers %d times", attempts))
+}
 
-	err = h.RabbitChannel.Publish(
-		"",
-		"ocr_queue",
-		false,
-		false,
-		amqp.Publishing{
-			ContentType:   "application/json",
-			Body:          []byte(taskMessage),
-			CorrelationId: taskID,
-		},
-	)
+// recordPublishFailure stores why the broker did not accept a task, e.g. a
+// missing confirm or a return because no queue was bound for it.
+func (h *TaskHandler) recordPublishFailure(ctx context.Context, meetingId, taskID, status string, publishErr error) {
+	err := h.RedisManager.UpdateTask(ctx, meetingId, taskID, redis.TaskUpdate{
+		Status:    status,
+		LastError: publishErr.Error(),
+	})
 	if err != nil {
-		h.RedisManager.UpdateTaskStatus(ctx, meetingId, taskID, "failed")
-		return fmt.Errorf("failed to publish task to RabbitMQ: %w", err)
+		log.Printf("Failed to record publish failure for task %s: %v", taskID, err)
 	}
-
-	log.Printf("Task sent to ocr_queue: %s", taskMessage)
-	return nil
 }
 
-func (h *TaskHandler) publishLog(logMessage LogMessage) error {
-	body, err := json.Marshal(logMessage)
+func (h *TaskHandler) publishLog(logMessage contracts.LogMessage) error {
+	body, err := contracts.Encode(&logMessage)
 	if err != nil {
 		return fmt.Errorf("failed to marshal log message: %w", err)
 	}
 
-	err = h.RabbitChannel.Publish(
-		"",           // exchange
-		"logs_queue", // routing key
-		false,        // mandatory
-		false,        // immediate
-		amqp.Publishing{
+	err = h.Broker.Publish(
+		"logs_queue",
+		messaging.Message{
 			ContentType: "application/json",
 			Body:        body,
 		},
@@ -194,6 +185,6 @@ func (h *TaskHandler) publishLog(logMessage LogMessage) error {
 		return fmt.Errorf("failed to publish log: %w", err)
 	}
 
-	log.Println("Log sent to RabbitMQ:", string(body))
+	log.Println("Log sent to broker:", string(body))
 	return nil
 }
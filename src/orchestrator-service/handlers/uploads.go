@@ -0,0 +1,454 @@
+package handlers
+
+import (
+	"context"
+	"contracts"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"orchestrator-service/events"
+	"orchestrator-service/redis"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	goredis "github.com/go-redis/redis/v8"
+)
+
+const (
+	uploadSessionTTL    = 24 * time.Hour
+	uploadContentType   = "application/offset+octet-stream"
+	uploadPartialSubdir = ".uploads"
+)
+
+type uploadKind struct {
+	BasePath string
+	Types    map[string]string
+	MaxSize  int64
+}
+
+var uploadKinds = map[string]uploadKind{
+	"audio":      {BasePath: contracts.TranscriptionDir, Types: audioTypes, MaxSize: maxAudioSize},
+	"screenshot": {BasePath: contracts.OCRDir, Types: imageTypes, MaxSize: maxScreenshotSize},
+}
+
+var checksumPattern = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+
+// uploadLocks serialises PATCH requests for the same upload.
+var uploadLocks sync.Map
+
+type CreateUploadRequest struct {
+	MeetingId string `json:"meeting_id"`
+	Kind      string `json:"kind"`
+	Size      int64  `json:"size"`
+	Checksum  string `json:"checksum"`
+}
+
+type UploadResponse struct {
+	UploadId string `json:"upload_id"`
+	Offset   int64  `json:"offset"`
+	Size     int64  `json:"size"`
+}
+
+func partialUploadPath(session redis.UploadSession) string {
+	kind := uploadKinds[session.Kind]
+	return filepath.Join(contracts.SharedDir(kind.BasePath), session.MeetingId, uploadPartialSubdir, session.UploadId+".part")
+}
+
+// CreateUpload opens a resumable upload session. The client then sends the
+// file with one or more PATCH requests, resuming from the offset reported by
+// HEAD after a dropped connection.
+func (app *Config) CreateUpload(w http.ResponseWriter, r *http.Request) {
+	var req CreateUploadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		log.Printf("Error decoding JSON: %v", err)
+		return
+	}
+
+	if req.MeetingId == "" {
+		http.Error(w, "Missing meeting_id in request body", http.StatusBadRequest)
+		return
+	}
+
+	kind, ok := uploadKinds[req.Kind]
+	if !ok {
+		http.Error(w, "Invalid kind, expected audio or screenshot", http.StatusBadRequest)
+		return
+	}
+
+	if req.Size <= 0 {
+		http.Error(w, "Invalid size, expected a positive number of bytes", http.StatusBadRequest)
+		return
+	}
+	if req.Size > kind.MaxSize {
+		http.Error(w, fmt.Sprintf("Upload exceeds the %d byte limit for %s", kind.MaxSize, req.Kind), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if !checksumPattern.MatchString(req.Checksum) {
+		http.Error(w, "Invalid checksum, expected sha256:<hex>", http.StatusBadRequest)
+		return
+	}
+
+	if !app.authorizeMeeting(w, r, req.MeetingId, true) {
+		return
+	}
+
+	ctx := context.Background()
+
+	uploadId, err := randomHex(16)
+	if err != nil {
+		http.Error(w, "Failed to create upload", http.StatusInternalServerError)
+		log.Printf("Failed to generate upload id: %v", err)
+		return
+	}
+
+	session := redis.UploadSession{
+		UploadId:  uploadId,
+		MeetingId: req.MeetingId,
+		Kind:      req.Kind,
+		Size:      req.Size,
+		Checksum:  req.Checksum,
+	}
+
+	partialPath := partialUploadPath(session)
+	if err = os.MkdirAll(filepath.Dir(partialPath), os.ModePerm); err != nil {
+		http.Error(w, "Unable to create directory", http.StatusInternalServerError)
+		log.Printf("Error creating directory: %v", err)
+		return
+	}
+
+	out, err := os.Create(partialPath)
+	if err != nil {
+		http.Error(w, "Unable to create upload", http.StatusInternalServerError)
+		log.Printf("Error creating partial upload file: %v", err)
+		return
+	}
+	out.Close()
+
+	if err = app.RedisManager.CreateUploadSession(ctx, session, uploadSessionTTL); err != nil {
+		os.Remove(partialPath)
+		http.Error(w, "Failed to create upload", http.StatusInternalServerError)
+		log.Printf("Failed to save upload session: %v", err)
+		return
+	}
+
+	log.Printf("Created %s upload %s for meeting_id=%s (%d bytes)", req.Kind, uploadId, req.MeetingId, req.Size)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/uploads/"+uploadId)
+	w.Header().Set("Upload-Offset", "0")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(UploadResponse{UploadId: uploadId, Offset: 0, Size: req.Size})
+}
+
+// UploadOffset reports how many bytes of an upload the server already has.
+func (app *Config) UploadOffset(w http.ResponseWriter, r *http.Request) {
+	session, ok := app.loadUploadSession(w, chi.URLParam(r, "id"))
+	if !ok {
+		return
+	}
+
+	if !app.authorizeMeeting(w, r, session.MeetingId, false) {
+		return
+	}
+
+	offset, err := currentUploadOffset(session)
+	if err != nil {
+		http.Error(w, "Failed to read upload", http.StatusInternalServerError)
+		log.Printf("Failed to stat upload %s: %v", session.UploadId, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Upload-Offset", strconv.FormatInt(offset, 10))
+	w.Header().Set("Upload-Length", strconv.FormatInt(session.Size, 10))
+	w.WriteHeader(http.StatusOK)
+}
+
+// AppendUpload writes the request body at the offset given in the
+// Upload-Offset header. Once every byte has arrived the checksum is verified
+// and the file is handed to the regular audio or screenshot pipeline. If that
+// fails the upload stays open, and an empty PATCH at the final offset retries.
+func (app *Config) AppendUpload(w http.ResponseWriter, r *http.Request) {
+	uploadId := chi.URLParam(r, "id")
+
+	session, ok := app.loadUploadSession(w, uploadId)
+	if !ok {
+		return
+	}
+
+	// Files completed after the meeting ended would add tasks to a pipeline
+	// that may already be summarizing, so only running meetings accept data.
+	if !app.authorizeMeeting(w, r, session.MeetingId, true) {
+		return
+	}
+
+	// Locks are only created for authorized requests to existing uploads.
+	// The session is read again under the lock, since a concurrent request
+	// may have completed the upload meanwhile.
+	lock, _ := uploadLocks.LoadOrStore(uploadId, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	if session, ok = app.loadUploadSession(w, uploadId); !ok {
+		return
+	}
+
+	if r.Header.Get("Content-Type") != uploadContentType {
+		http.Error(w, "Content-Type must be "+uploadContentType, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	clientOffset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil {
+		http.Error(w, "Missing or invalid Upload-Offset header", http.StatusBadRequest)
+		return
+	}
+
+	offset, err := currentUploadOffset(session)
+	if err != nil {
+		http.Error(w, "Failed to read upload", http.StatusInternalServerError)
+		log.Printf("Failed to stat upload %s: %v", session.UploadId, err)
+		return
+	}
+
+	if clientOffset != offset {
+		w.Header().Set("Upload-Offset", strconv.FormatInt(offset, 10))
+		http.Error(w, "Upload-Offset does not match the current offset", http.StatusConflict)
+		return
+	}
+
+	out, err := os.OpenFile(partialUploadPath(session), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		http.Error(w, "Failed to open upload", http.StatusInternalServerError)
+		log.Printf("Failed to open upload %s: %v", session.UploadId, err)
+		return
+	}
+
+	written, copyErr := io.Copy(out, io.LimitReader(r.Body, session.Size-offset))
+	closeErr := out.Close()
+	offset += written
+
+	w.Header().Set("Upload-Offset", strconv.FormatInt(offset, 10))
+
+	if copyErr != nil || closeErr != nil {
+		log.Printf("Upload %s interrupted at offset %d: %v %v", session.UploadId, offset, copyErr, closeErr)
+		http.Error(w, "Upload interrupted, resume from Upload-Offset", http.StatusInternalServerError)
+		return
+	}
+
+	if offset < session.Size {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	filePath, err := app.completeUpload(session)
+	if errors.Is(err, errChecksumMismatch) {
+		http.Error(w, "Checksum mismatch, upload discarded", http.StatusUnprocessableEntity)
+		return
+	}
+	if errors.Is(err, errUnsupportedContent) {
+		http.Error(w, "Unsupported "+session.Kind+" format, upload discarded", http.StatusUnsupportedMediaType)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to process upload", http.StatusInternalServerError)
+		log.Printf("Failed to complete upload %s: %v", session.UploadId, err)
+		return
+	}
+
+	log.Printf("Upload %s completed: %s", session.UploadId, filePath)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+var (
+	errChecksumMismatch   = errors.New("checksum mismatch")
+	errUnsupportedContent = errors.New("unsupported content type")
+)
+
+func (app *Config) completeUpload(session redis.UploadSession) (string, error) {
+	ctx := context.Background()
+	partialPath := partialUploadPath(session)
+
+	sum, err := fileChecksum(partialPath)
+	if err != nil {
+		return "", err
+	}
+
+	if sum != session.Checksum {
+		log.Printf("Checksum mismatch for upload %s: expected %s, got %s", session.UploadId, session.Checksum, sum)
+		app.discardUpload(session)
+		return "", errChecksumMismatch
+	}
+
+	kind := uploadKinds[session.Kind]
+	ext, err := sniffPartialUpload(partialPath, kind.Types)
+	if err != nil {
+		log.Printf("Rejected upload %s: %v", session.UploadId, err)
+		app.discardUpload(session)
+		return "", errUnsupportedContent
+	}
+
+	filePath := filepath.Join(contracts.SharedDir(kind.BasePath), session.MeetingId, session.UploadId+ext)
+	if err = os.Rename(partialPath, filePath); err != nil {
+		return "", err
+	}
+
+	if err = app.dispatchUpload(session, filePath); err != nil {
+		// Keep the session and put the file back, so the client can complete
+		// the upload again with an empty PATCH at the final offset.
+		if renameErr := os.Rename(filePath, partialPath); renameErr != nil {
+			log.Printf("Failed to restore upload %s: %v", session.UploadId, renameErr)
+		}
+		return "", err
+	}
+
+	if err = app.RedisManager.DeleteUploadSession(ctx, session.UploadId); err != nil {
+		log.Printf("Failed to delete upload session %s: %v", session.UploadId, err)
+	}
+	uploadLocks.Delete(session.UploadId)
+
+	return filePath, nil
+}
+
+// dispatchUpload hands a completed file to the audio or screenshot pipeline.
+// On error the file is left at filePath.
+func (app *Config) dispatchUpload(session redis.UploadSession, filePath string) error {
+	switch session.Kind {
+	case "audio":
+		if err := app.dispatchAudio(session.MeetingId, filePath); err != nil {
+			return err
+		}
+		app.Events.Publish(events.Event{MeetingId: session.MeetingId, Type: "audio_received"})
+	case "screenshot":
+		if err := app.TaskHandler.SendOcrTask(session.MeetingId, filePath); err != nil {
+			return fmt.Errorf("failed to send OCR task: %w", err)
+		}
+		app.Events.Publish(events.Event{MeetingId: session.MeetingId, Type: "screenshot_received"})
+	}
+	return nil
+}
+
+func (app *Config) discardUpload(session redis.UploadSession) {
+	os.Remove(partialUploadPath(session))
+	if err := app.RedisManager.DeleteUploadSession(context.Background(), session.UploadId); err != nil {
+		log.Printf("Failed to delete upload session %s: %v", session.UploadId, err)
+	}
+	uploadLocks.Delete(session.UploadId)
+}
+
+func sniffPartialUpload(path string, allowed map[string]string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	return sniffContentType(file, allowed)
+}
+
+func (app *Config) loadUploadSession(w http.ResponseWriter, uploadId string) (redis.UploadSession, bool) {
+	session, err := app.RedisManager.GetUploadSession(context.Background(), uploadId)
+	if errors.Is(err, goredis.Nil) {
+		http.Error(w, "Upload not found", http.StatusNotFound)
+		return session, false
+	}
+	if err != nil {
+		http.Error(w, "Failed to get upload", http.StatusInternalServerError)
+		log.Printf("Failed to get upload session %s: %v", uploadId, err)
+		return session, false
+	}
+	return session, true
+}
+
+func currentUploadOffset(session redis.UploadSession) (int64, error) {
+	info, err := os.Stat(partialUploadPath(session))
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+func fileChecksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// CleanupExpiredUploads periodically removes the partial files and locks of
+// upload sessions that expired in Redis before they were completed.
+func (app *Config) CleanupExpiredUploads(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		app.sweepExpiredUploads(context.Background())
+	}
+}
+
+func (app *Config) sweepExpiredUploads(ctx context.Context) {
+	for _, kind := range uploadKinds {
+		pattern := filepath.Join(contracts.SharedDir(kind.BasePath), "*", uploadPartialSubdir, "*.part")
+		partials, err := filepath.Glob(pattern)
+		if err != nil {
+			log.Printf("Failed to list partial uploads: %v", err)
+			continue
+		}
+
+		for _, partialPath := range partials {
+			// A session is saved just after its file is created, so recent
+			// files may belong to a session that does not exist yet.
+			info, err := os.Stat(partialPath)
+			if err != nil || time.Since(info.ModTime()) < time.Hour {
+				continue
+			}
+
+			uploadId := strings.TrimSuffix(filepath.Base(partialPath), ".part")
+			if app.uploadSessionExists(ctx, uploadId) {
+				continue
+			}
+
+			if err = os.Remove(partialPath); err != nil {
+				log.Printf("Failed to delete expired upload %s: %v", partialPath, err)
+				continue
+			}
+			uploadLocks.Delete(uploadId)
+			log.Printf("Deleted expired upload %s", partialPath)
+		}
+	}
+
+	// Locks of sessions whose files are already gone.
+	uploadLocks.Range(func(key, _ interface{}) bool {
+		if !app.uploadSessionExists(ctx, key.(string)) {
+			uploadLocks.Delete(key)
+		}
+		return true
+	})
+}
+
+// uploadSessionExists treats Redis errors as existing, so a Redis outage
+// never deletes uploads.
+func (app *Config) uploadSessionExists(ctx context.Context, uploadId string) bool {
+	_, err := app.RedisManager.GetUploadSession(ctx, uploadId)
+	return !errors.Is(err, goredis.Nil)
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+)
+
+const (
+	maxScreenshotSize = 20 << 20
+	maxAudioSize      = 100 << 20
+)
+
+var meetingIdPattern = regexp.MustCompile(`^[a-zA-Z0-9]{10}$`)
+
+// Content types accepted per upload kind, mapped to the extension used for
+// the server-generated file name.
+var (
+	// Screenshots are embedded in the PDF report, which only supports
+	// formats gofpdf can read.
+	imageTypes = map[string]string{
+		"image/png":  ".png",
+		"image/jpeg": ".jpg",
+	}
+	audioTypes = map[string]string{
+		"video/webm":      ".webm",
+		"application/ogg": ".ogg",
+		"audio/mpeg":      ".mp3",
+		"video/mp4":       ".mp4",
+	}
+	recordingTypes = map[string]string{
+		"video/webm": ".webm",
+		"video/mp4":  ".mp4",
+		"audio/mpeg": ".mp3",
+	}
+)
+
+func isValidMeetingId(meetingId string) bool {
+	return meetingIdPattern.MatchString(meetingId)
+}
+
+// sniffContentType inspects the first bytes of file and returns the
+// extension for its content type if it is one of the allowed ones. The file
+// is rewound afterwards.
+func sniffContentType(file io.ReadSeeker, allowed map[string]string) (string, error) {
+	buf := make([]byte, 512)
+	n, err := io.ReadFull(file, buf)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	contentType := http.DetectContentType(buf[:n])
+	ext, ok := allowed[contentType]
+	if !ok {
+		return "", fmt.Errorf("unsupported content type %s", contentType)
+	}
+	return ext, nil
+}
+
+func isRequestTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
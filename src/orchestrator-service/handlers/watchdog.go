@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"orchestrator-service/pipeline"
+	"time"
+)
+
+// RunTaskWatchdog periodically looks for tasks that have been pending longer
+// than their stage deadline. Such tasks are dispatched again until they run
+// out of attempts, after which they are marked as failed.
+func (app *Config) RunTaskWatchdog(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx := context.Background()
+		for _, stage := range app.Workflow.Stages {
+			if stage.Deadline == 0 {
+				continue
+			}
+			app.checkStuckTasks(ctx, stage)
+		}
+	}
+}
+
+func (app *Config) checkStuckTasks(ctx context.Context, stage pipeline.Stage) {
+	tasks, err := app.RedisManager.GetTasksOfType(ctx, stage.Name)
+	if err != nil {
+		log.Printf("Watchdog failed to read %s tasks: %v", stage.Name, err)
+		return
+	}
+
+	for _, task := range tasks {
+		if task.FinishedAt != nil {
+			if err = app.RedisManager.UnindexTask(ctx, task.TaskType, task.TaskId); err != nil {
+				log.Printf("Watchdog failed to unindex task %s: %v", task.TaskId, err)
+			}
+			continue
+		}
+
+		if task.Status != "pending" {
+			continue
+		}
+		meetingId := task.MeetingId
+
+		// Tasks still in the outbox are the relay's responsibility. Tasks
+		// recorded before the outbox existed have no sent_at either, but were
+		// published directly and are treated as sent.
+		if task.SentAt == nil {
+			queued, err := app.RedisManager.InOutbox(ctx, task.TaskId)
+			if err != nil {
+				log.Printf("Watchdog failed to look up task %s in the outbox: %v", task.TaskId, err)
+				continue
+			}
+			if queued {
+				continue
+			}
+		}
+
+		if time.Since(task.UpdatedAt) < time.Duration(stage.Deadline) {
+			continue
+		}
+
+		if task.Attempts >= stage.MaxAttempts {
+			log.Printf("Task %s for meeting_id=%s exceeded %d attempts, marking as failed", task.TaskId, meetingId, stage.MaxAttempts)
+			app.TaskHandler.FailTask(ctx, task, fmt.Sprintf("no acknowledgement after %d attempts", task.Attempts))
+			continue
+		}
+
+		log.Printf("Task %s for meeting_id=%s missed its %s deadline, dispatching again", task.TaskId, meetingId, time.Duration(stage.Deadline))
+		err = app.TaskHandler.RedispatchTask(meetingId, task.TaskId)
+		if err != nil {
+			log.Printf("Watchdog failed to re-dispatch task %s: %v", task.TaskId, err)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const defaultMaxAttempts = 3
+
+// Duration is a time.Duration written as a string such as "10m" in workflow
+// definitions.
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(parsed)
+	return nil
+}
+
+// Stage is one step of the meeting pipeline. Source stages are fed by
+// uploads while the meeting is running; every other stage is dispatched by
+// the orchestrator once the meeting has ended and all of its dependencies
+// have completed. A task still pending after Deadline is dispatched again,
+// up to MaxAttempts times in total; a zero Deadline disables the watchdog.
+type Stage struct {
+	Name        string   `json:"name"`
+	Queue       string   `json:"queue"`
+	DependsOn   []string `json:"depends_on"`
+	Source      bool     `json:"source"`
+	Deadline    Duration `json:"deadline"`
+	MaxAttempts int      `json:"max_attempts"`
+}
+
+type Workflow struct {
+	Stages []Stage `json:"stages"`
+}
+
+// StageState counts the tasks of one stage for a meeting.
+type StageState struct {
+	Total     int
+	Completed int
+}
+
+// Done reports whether every task of the stage has completed. A source stage
+// without tasks counts as done, so a meeting without screenshots does not
+// wait for OCR.
+func (s StageState) Done(stage Stage) bool {
+	if s.Total == 0 {
+		return stage.Source
+	}
+	return s.Completed == s.Total
+}
+
+func DefaultWorkflow() *Workflow {
+	return &Workflow{
+		Stages: []Stage{
+			{
+				Name:        "transcription",
+				Queue:       "transcription_queue",
+				Source:      true,
+				Deadline:    Duration(10 * time.Minute),
+				MaxAttempts: defaultMaxAttempts,
+			},
+			{
+				Name:        "ocr",
+				Queue:       "ocr_queue",
+				Source:      true,
+				Deadline:    Duration(5 * time.Minute),
+				MaxAttempts: defaultMaxAttempts,
+			},
+			{
+				Name:        "summary",
+				Queue:       "summary_queue",
+				DependsOn:   []string{"transcription"},
+				Deadline:    Duration(5 * time.Minute),
+				MaxAttempts: defaultMaxAttempts,
+			},
+			{
+				Name:        "report",
+				Queue:       "report_queue",
+				DependsOn:   []string{"transcription", "ocr", "summary"},
+				Deadline:    Duration(5 * time.Minute),
+				MaxAttempts: defaultMaxAttempts,
+			},
+			{
+				Name:        "email",
+				Queue:       "email_queue",
+				DependsOn:   []string{"report"},
+				Deadline:    Duration(5 * time.Minute),
+				MaxAttempts: defaultMaxAttempts,
+			},
+		},
+	}
+}
+
+// Load reads a workflow definition from a JSON file, or returns the default
+// workflow when path is empty.
+func Load(path string) (*Workflow, error) {
+	if path == "" {
+		return DefaultWorkflow(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow definition: %w", err)
+	}
+
+	var workflow Workflow
+	if err = json.Unmarshal(data, &workflow); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow definition: %w", err)
+	}
+
+	if err = workflow.Validate(); err != nil {
+		return nil, err
+	}
+
+	for i := range workflow.Stages {
+		if workflow.Stages[i].MaxAttempts <= 0 {
+			workflow.Stages[i].MaxAttempts = defaultMaxAttempts
+		}
+	}
+
+	return &workflow, nil
+}
+
+func (w *Workflow) Validate() error {
+	stages := make(map[string]Stage, len(w.Stages))
+	for _, stage := range w.Stages {
+		// Stage claims share a hash with the orchestrator's own claims, whose
+		// names start with an underscore.
+		if stage.Name == "" || strings.HasPrefix(stage.Name, "_") {
+			return fmt.Errorf("invalid stage name %q", stage.Name)
+		}
+		if stage.Queue == "" {
+			return fmt.Errorf("stage %s has no queue", stage.Name)
+		}
+		if stage.Deadline < 0 {
+			return fmt.Errorf("stage %s has a negative deadline", stage.Name)
+		}
+		if _, exists := stages[stage.Name]; exists {
+			return fmt.Errorf("duplicate stage %s", stage.Name)
+		}
+		stages[stage.Name] = stage
+	}
+
+	for _, stage := range w.Stages {
+		if stage.Source && len(stage.DependsOn) > 0 {
+			return fmt.Errorf("source stage %s cannot have dependencies", stage.Name)
+		}
+		for _, dep := range stage.DependsOn {
+			if _, ok := stages[dep]; !ok {
+				return fmt.Errorf("stage %s depends on unknown stage %s", stage.Name, dep)
+			}
+		}
+	}
+
+	// Depth-first search for cycles.
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	marks := make(map[string]int, len(stages))
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch marks[name] {
+		case visiting:
+			return fmt.Errorf("workflow has a cycle through stage %s", name)
+		case visited:
+			return nil
+		}
+		marks[name] = visiting
+		for _, dep := range stages[name].DependsOn {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		marks[name] = visited
+		return nil
+	}
+	for _, stage := range w.Stages {
+		if err := visit(stage.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *Workflow) Stage(name string) (Stage, bool) {
+	for _, stage := range w.Stages {
+		if stage.Name == name {
+			return stage, true
+		}
+	}
+	return Stage{}, false
+}
+
+func (w *Workflow) Queues() []string {
+	queues := make([]string, 0, len(w.Stages))
+	for _, stage := range w.Stages {
+		queues = append(queues, stage.Queue)
+	}
+	return queues
+}
+
+// ReadyStages returns the stages that have not been dispatched yet and whose
+// dependencies are all done.
+func (w *Workflow) ReadyStages(state map[string]StageState) []Stage {
+	var ready []Stage
+	for _, stage := range w.Stages {
+		if stage.Source || state[stage.Name].Total > 0 {
+			continue
+		}
+		if w.dependenciesDone(stage, state) {
+			ready = append(ready, stage)
+		}
+	}
+	return ready
+}
+
+// Finished reports whether every stage of the workflow is done.
+func (w *Workflow) Finished(state map[string]StageState) bool {
+	for _, stage := range w.Stages {
+		if !state[stage.Name].Done(stage) {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *Workflow) dependenciesDone(stage Stage, state map[string]StageState) bool {
+	for _, dep := range stage.DependsOn {
+		depStage, _ := w.Stage(dep)
+		if !state[dep].Done(depStage) {
+			return false
+		}
+	}
+	return true
+}
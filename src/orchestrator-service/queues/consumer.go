@@ -0,0 +1,14 @@
+package queues
+
+import (
+	"messaging"
+)
+
+// Consumer registers the orchestrator's consumers on the broker, which keeps
+// them subscribed across reconnects.
+type Consumer struct {
+	Broker messaging.Broker
+	// Parked, if set, is called with the ID and the number of attempts of
+	// every task moved to a parking queue.
+	Parked func(taskID string, attempts int)
+}
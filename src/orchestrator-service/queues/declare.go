@@ -0,0 +1,13 @@
+package queues
+
+import (
+	"messaging"
+)
+
+func DeclareQueues(broker messaging.Broker, names []string) error {
+	queues := make([]messaging.Queue, len(names))
+	for i, name := range names {
+		queues[i] = messaging.Queue{Name: name}
+	}
+	return broker.Declare(queues...)
+}
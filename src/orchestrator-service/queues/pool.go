@@ -0,0 +1,114 @@
+package queues
+
+import (
+	"errors"
+	"hash/fnv"
+	"log"
+	"messaging"
+	"time"
+)
+
+// ErrDiscard marks a message that can never be handled successfully. It is
+// rejected without requeueing instead of being retried.
+var ErrDiscard = errors.New("discard message")
+
+// PoolOptions size the worker pool. A message whose handler keeps failing is
+// handled up to Retry.MaxAttempts times, waiting Retry.Delay(n) after the
+// n-th failure, and then parked.
+type PoolOptions struct {
+	Prefetch int
+	Workers  int
+	Retry    RetryPolicy
+}
+
+// ConsumeOrdered consumes queue with manual acknowledgements and hands the
+// deliveries to a fixed pool of workers. Messages with the same key always go
+// to the same worker, so they are handled in order, while messages with
+// different keys are handled in parallel. A message is acked once handler
+// returns nil and dropped if it returns ErrDiscard. Any other error is retried
+// by the same worker before it takes the next message, since requeueing to the
+// broker would let later messages with the same key overtake it. Messages that
+// still fail after the last attempt are moved to ParkingQueue(queue), which
+// the caller declares, so they do not hold up the other keys of the worker.
+func (c *Consumer) ConsumeOrdered(queue string, options PoolOptions, key func(messaging.Delivery) string, handler func(messaging.Delivery) error) {
+	workers := make([]chan messaging.Delivery, options.Workers)
+	for i := range workers {
+		// With at most Prefetch unacked deliveries in flight the dispatcher
+		// never blocks on a busy worker.
+		workers[i] = make(chan messaging.Delivery, options.Prefetch)
+		go func(deliveries <-chan messaging.Delivery) {
+			for msg := range deliveries {
+				c.settle(queue, msg, handleWithRetry(queue, options.Retry, msg, handler))
+			}
+		}(workers[i])
+	}
+
+	c.Broker.Consume(messaging.Consumer{
+		Queue:    queue,
+		Prefetch: options.Prefetch,
+		Handler: func(msg messaging.Delivery) {
+			workers[workerFor(key(msg), len(workers))] <- msg
+		},
+	})
+}
+
+func workerFor(key string, workers int) int {
+	hash := fnv.New32a()
+	hash.Write([]byte(key))
+	return int(hash.Sum32() % uint32(workers))
+}
+
+// handleWithRetry runs handler until it succeeds, returns ErrDiscard or runs
+// out of attempts.
+func handleWithRetry(queue string, policy RetryPolicy, msg messaging.Delivery, handler func(messaging.Delivery) error) error {
+	for attempt := 1; ; attempt++ {
+		err := handler(msg)
+		if err == nil || errors.Is(err, ErrDiscard) || attempt >= policy.MaxAttempts {
+			return err
+		}
+
+		delay := policy.Delay(attempt)
+		log.Printf("Failed to handle message from '%s' (attempt %d), retrying in %s: %v", queue, attempt, delay, err)
+		time.Sleep(delay)
+	}
+}
+
+func (c *Consumer) settle(queue string, msg messaging.Delivery, err error) {
+	switch {
+	case err == nil:
+		err = msg.Ack()
+	case errors.Is(err, ErrDiscard):
+		log.Printf("Discarding message from '%s': %v", queue, err)
+		err = msg.Nack(false)
+	default:
+		err = c.park(queue, msg, err)
+	}
+
+	// The channel is gone after a reconnect; RabbitMQ redelivers the message.
+	if err != nil {
+		log.Printf("Failed to settle message from '%s': %v", queue, err)
+	}
+}
+
+// park moves a message that ran out of attempts to the parking queue. If
+// that fails it is requeued, giving up its place in the order rather than
+// being lost.
+func (c *Consumer) park(queue string, msg messaging.Delivery, handlerErr error) error {
+	target := ParkingQueue(queue)
+	err := c.Broker.Publish(
+		target,
+		messaging.Message{
+			Headers:       msg.Headers,
+			ContentType:   msg.ContentType,
+			CorrelationId: msg.CorrelationId,
+			Body:          msg.Body,
+		},
+	)
+	if err != nil {
+		log.Printf("Failed to park message from '%s', requeueing: %v", queue, err)
+		return msg.Nack(true)
+	}
+
+	log.Printf("Message from '%s' failed too often, parked in '%s': %v", queue, target, handlerErr)
+	return msg.Ack()
+}
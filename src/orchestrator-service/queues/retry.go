@@ -0,0 +1,121 @@
+package queues
+
+import (
+	"fmt"
+	"log"
+	"messaging"
+	"time"
+)
+
+// retryAttemptHeader counts how many times a task was rejected by a worker.
+// It is set by the retry router rather than relying on x-death, which the
+// broker only maintains for messages it dead-letters itself.
+const retryAttemptHeader = "x-retry-attempt"
+
+// RetryPolicy controls how rejected tasks are retried. The n-th retry waits
+// InitialDelay * 2^(n-1); after MaxAttempts deliveries a task is parked.
+type RetryPolicy struct {
+	MaxAttempts  int
+	InitialDelay time.Duration
+}
+
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	return p.InitialDelay * time.Duration(1<<(attempt-1))
+}
+
+func RetryQueue(queue string, attempt int) string {
+	return fmt.Sprintf("%s.retry.%d", queue, attempt)
+}
+
+func ParkingQueue(queue string) string {
+	return queue + ".parking"
+}
+
+// DeclareTaskQueues declares the task queues together with their retry
+// topology:
+//   - <queue> dead-letters rejected messages to <queue>.dead, where the retry
+//     router picks them up,
+//   - <queue>.retry.<n> holds a message for the n-th backoff delay and then
+//     moves it back to <queue>,
+//   - <queue>.parking keeps tasks that ran out of attempts for inspection.
+func DeclareTaskQueues(broker messaging.Broker, queues []string, policy RetryPolicy) error {
+	var declared []messaging.Queue
+	for _, queue := range queues {
+		declared = append(declared, messaging.Queue{Name: queue, DeadLetter: true})
+
+		for attempt := 1; attempt < policy.MaxAttempts; attempt++ {
+			declared = append(declared, messaging.Queue{
+				Name:        RetryQueue(queue, attempt),
+				Delay:       policy.Delay(attempt),
+				DelayTarget: queue,
+			})
+		}
+
+		declared = append(declared, messaging.Queue{Name: ParkingQueue(queue)})
+	}
+	return broker.Declare(declared...)
+}
+
+// RouteDeadLetters consumes the dead-letter queue of a task queue and moves
+// each rejected task to the next retry queue, or to the parking queue once
+// it has been delivered MaxAttempts times.
+func (c *Consumer) RouteDeadLetters(queue string, policy RetryPolicy) {
+	c.Broker.Consume(messaging.Consumer{
+		Queue: messaging.DeadLetterQueue(queue),
+		Handler: func(msg messaging.Delivery) {
+			c.routeDeadLetter(queue, policy, msg)
+		},
+	})
+}
+
+func (c *Consumer) routeDeadLetter(queue string, policy RetryPolicy, msg messaging.Delivery) {
+	attempt := retryAttempt(msg.Headers) + 1
+
+	headers := map[string]interface{}{}
+	for key, value := range msg.Headers {
+		headers[key] = value
+	}
+	headers[retryAttemptHeader] = int32(attempt)
+
+	target := RetryQueue(queue, attempt)
+	if attempt >= policy.MaxAttempts {
+		target = ParkingQueue(queue)
+	}
+
+	err := c.Broker.Publish(
+		target,
+		messaging.Message{
+			Headers:       headers,
+			ContentType:   msg.ContentType,
+			CorrelationId: msg.CorrelationId,
+			Body:          msg.Body,
+		},
+	)
+	if err != nil {
+		log.Printf("Failed to move task %s to '%s': %v", msg.CorrelationId, target, err)
+		_ = msg.Nack(true)
+		return
+	}
+
+	if target == ParkingQueue(queue) {
+		log.Printf("Task %s failed %d times, parked in '%s'", msg.CorrelationId, attempt, target)
+		if c.Parked != nil {
+			c.Parked(msg.CorrelationId, attempt)
+		}
+	} else {
+		log.Printf("Task %s failed (attempt %d), retrying in %s", msg.CorrelationId, attempt, policy.Delay(attempt))
+	}
+	_ = msg.Ack()
+}
+
+func retryAttempt(headers map[string]interface{}) int {
+	switch value := headers[retryAttemptHeader].(type) {
+	case int32:
+		return int(value)
+	case int64:
+		return int(value)
+	case int:
+		return value
+	}
+	return 0
+}
@@ -0,0 +1,63 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+	"time"
+)
+
+// The outbox is a sorted set of task IDs scored by the time they are due to
+// be published. Entries are removed once the broker has confirmed the task.
+const outboxKey = "tasks:outbox"
+
+func outboxScore(due time.Time) float64 {
+	return float64(due.UnixMilli())
+}
+
+// DueOutboxTasks returns up to limit task IDs that are due to be published.
+func (r *RedisManager) DueOutboxTasks(ctx context.Context, now time.Time, limit int64) ([]string, error) {
+	return r.Client.ZRangeByScore(ctx, outboxKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatFloat(outboxScore(now), 'f', 0, 64),
+		Count: limit,
+	}).Result()
+}
+
+// markSentScript only stamps the task if it still exists, so a meeting that
+// was cleaned up while its task was being published leaves no partial record.
+var markSentScript = redis.NewScript(`
+redis.call("ZREM", KEYS[1], ARGV[1])
+if redis.call("EXISTS", KEYS[2]) == 1 then
+	redis.call("HSET", KEYS[2], "sent_at", ARGV[2], "updated_at", ARGV[2])
+end
+return 0
+`)
+
+// MarkTaskSent removes a task from the outbox and records when the broker
+// accepted it.
+func (r *RedisManager) MarkTaskSent(ctx context.Context, taskID string) error {
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	return markSentScript.Run(ctx, r.Client, []string{outboxKey, taskKey(taskID)}, taskID, now).Err()
+}
+
+// DeferOutboxTask postpones the next publish attempt of a task still in the
+// outbox.
+func (r *RedisManager) DeferOutboxTask(ctx context.Context, taskID string, until time.Time) error {
+	return r.Client.ZAddXX(ctx, outboxKey, &redis.Z{Score: outboxScore(until), Member: taskID}).Err()
+}
+
+// InOutbox reports whether a task is waiting in the outbox to be published.
+func (r *RedisManager) InOutbox(ctx context.Context, taskID string) (bool, error) {
+	err := r.Client.ZScore(ctx, outboxKey, taskID).Err()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// DropOutboxTask removes a task from the outbox without publishing it.
+func (r *RedisManager) DropOutboxTask(ctx context.Context, taskID string) error {
+	return r.Client.ZRem(ctx, outboxKey, taskID).Err()
+}
@@ -5,64 +5,32 @@ import (
 	"fmt"
 	"github.com/go-redis/redis/v8"
 	"log"
-	"strings"
+	"strconv"
+	"time"
 )
 
 type RedisManager struct {
 	Client *redis.Client
 }
 
-func (r *RedisManager) AddTask(ctx context.Context, meetingID, taskID string) error {
-	key := fmt.Sprintf("meeting:%s:tasks", meetingID)
-	return r.Client.HSet(ctx, key, taskID, "pending").Err()
+type UploadSession struct {
+	UploadId  string `json:"upload_id"`
+	MeetingId string `json:"meeting_id"`
+	Kind      string `json:"kind"`
+	Size      int64  `json:"size"`
+	Checksum  string `json:"checksum"`
 }
 
-func (r *RedisManager) UpdateTaskStatus(ctx context.Context, meetingId, taskId, status string) error {
-	key := "meeting:" + meetingId + ":tasks"
-	err := r.Client.HSet(ctx, key, taskId, status).Err()
-	if err != nil {
-		return err
-	}
-
-	log.Printf("Updated Redis for meeting_id=%s, task_id=%s, status=%s", meetingId, taskId, status)
-	return nil
-}
-
-func (r *RedisManager) AllTasksCompleted(ctx context.Context, meetingID string) (bool, error) {
-	key := fmt.Sprintf("meeting:%s:tasks", meetingID)
-	tasks, err := r.Client.HGetAll(ctx, key).Result()
-	if err != nil {
-		return false, err
-	}
-	for _, status := range tasks {
-		if status != "completed" {
-			return false, nil
-		}
-	}
-	return true, nil
-}
-
-func (r *RedisManager) AllTasksOfTypeCompleted(ctx context.Context, meetingID, taskType string) (bool, error) {
-	key := fmt.Sprintf("meeting:%s:tasks", meetingID)
-	tasks, err := r.Client.HGetAll(ctx, key).Result()
-	if err != nil {
-		return false, err
-	}
-
-	for taskID, status := range tasks {
-		if taskTypeInTaskID(taskID, taskType) && status != "completed" {
-			return false, nil
-		}
-	}
-	return true, nil
+func meetingStagesKey(meetingID string) string {
+	return fmt.Sprintf("meeting:%s:stages", meetingID)
 }
 
-func taskTypeInTaskID(taskID, taskType string) bool {
-	parts := strings.Split(taskID, "-")
-	if len(parts) < 2 {
-		return false
-	}
-	return parts[1] == taskType
+// ClaimStage atomically marks a pipeline stage as dispatched for a meeting.
+// Only the first caller gets true, so concurrent or duplicate acks cannot
+// dispatch the same stage twice. Stages that dispatch a task are claimed by
+// AddStageTask instead.
+func (r *RedisManager) ClaimStage(ctx context.Context, meetingID, stage string) (bool, error) {
+	return r.Client.HSetNX(ctx, meetingStagesKey(meetingID), stage, time.Now().UTC().Format(time.RFC3339Nano)).Result()
 }
 
 func (r *RedisManager) SetMeetingStatus(ctx context.Context, meetingID, status string) error {
@@ -91,7 +59,112 @@ func (r *RedisManager) GetMeetingEmail(ctx context.Context, meetingID string) (s
 	return r.Client.Get(ctx, key).Result()
 }
 
+func (r *RedisManager) SetMeetingRecipients(ctx context.Context, meetingID string, recipients []string) error {
+	key := fmt.Sprintf("meeting:%s:recipients", meetingID)
+
+	values := make([]interface{}, len(recipients))
+	for i, recipient := range recipients {
+		values[i] = recipient
+	}
+
+	pipe := r.Client.TxPipeline()
+	pipe.Del(ctx, key)
+	pipe.RPush(ctx, key, values...)
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Saved %d recipients for meeting_id=%s", len(recipients), meetingID)
+	return nil
+}
+
+// GetMeetingRecipients falls back to the single meeting email for meetings
+// created through GenerateMeetingId.
+func (r *RedisManager) GetMeetingRecipients(ctx context.Context, meetingID string) ([]string, error) {
+	key := fmt.Sprintf("meeting:%s:recipients", meetingID)
+	recipients, err := r.Client.LRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(recipients) > 0 {
+		return recipients, nil
+	}
+
+	email, err := r.GetMeetingEmail(ctx, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	return []string{email}, nil
+}
+
+// Report tokens live outside the meeting:<id>:* namespace so they survive
+// DeleteAllMeetingEntries and expire on their own once the download window ends.
+func (r *RedisManager) SetReportToken(ctx context.Context, meetingID, token string) error {
+	key := fmt.Sprintf("report:%s:token", meetingID)
+	return r.Client.Set(ctx, key, token, 0).Err()
+}
+
+func (r *RedisManager) GetReportToken(ctx context.Context, meetingID string) (string, error) {
+	key := fmt.Sprintf("report:%s:token", meetingID)
+	return r.Client.Get(ctx, key).Result()
+}
+
+func (r *RedisManager) ExpireReportToken(ctx context.Context, meetingID string, ttl time.Duration) error {
+	key := fmt.Sprintf("report:%s:token", meetingID)
+	return r.Client.Expire(ctx, key, ttl).Err()
+}
+
+func (r *RedisManager) CreateUploadSession(ctx context.Context, session UploadSession, ttl time.Duration) error {
+	key := fmt.Sprintf("upload:%s", session.UploadId)
+
+	pipe := r.Client.TxPipeline()
+	pipe.HSet(ctx, key, map[string]interface{}{
+		"meeting_id": session.MeetingId,
+		"kind":       session.Kind,
+		"size":       session.Size,
+		"checksum":   session.Checksum,
+	})
+	pipe.Expire(ctx, key, ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *RedisManager) GetUploadSession(ctx context.Context, uploadID string) (UploadSession, error) {
+	key := fmt.Sprintf("upload:%s", uploadID)
+	fields, err := r.Client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return UploadSession{}, err
+	}
+	if len(fields) == 0 {
+		return UploadSession{}, redis.Nil
+	}
+
+	size, err := strconv.ParseInt(fields["size"], 10, 64)
+	if err != nil {
+		return UploadSession{}, fmt.Errorf("invalid size for upload %s: %w", uploadID, err)
+	}
+
+	return UploadSession{
+		UploadId:  uploadID,
+		MeetingId: fields["meeting_id"],
+		Kind:      fields["kind"],
+		Size:      size,
+		Checksum:  fields["checksum"],
+	}, nil
+}
+
+func (r *RedisManager) DeleteUploadSession(ctx context.Context, uploadID string) error {
+	key := fmt.Sprintf("upload:%s", uploadID)
+	return r.Client.Del(ctx, key).Err()
+}
+
 func (r *RedisManager) DeleteAllMeetingEntries(ctx context.Context, meetingID string) error {
+	if err := r.deleteMeetingTasks(ctx, meetingID); err != nil {
+		log.Printf("Error deleting tasks for meeting_id=%s: %v", meetingID, err)
+		return err
+	}
+
 	pattern := fmt.Sprintf("meeting:%s:*", meetingID)
 	var cursor uint64
 	for {
@@ -0,0 +1,280 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Each task is stored as a hash under task:<id>. The set meeting:<id>:tasks
+// indexes tasks by meeting and tasks:type:<type> indexes the unfinished ones
+// by type.
+
+var ErrTaskNotFound = errors.New("task not found")
+
+type TaskInfo struct {
+	TaskId     string          `json:"task_id"`
+	MeetingId  string          `json:"meeting_id"`
+	TaskType   string          `json:"task_type"`
+	Status     string          `json:"status"`
+	Queue      string          `json:"queue"`
+	Attempts   int             `json:"attempts"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+	WorkerId   string          `json:"worker_id,omitempty"`
+	LastError  string          `json:"last_error,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+	SentAt     *time.Time      `json:"sent_at,omitempty"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+}
+
+// TaskUpdate carries the outcome reported for a task. Empty WorkerId and
+// LastError leave the stored values untouched.
+type TaskUpdate struct {
+	Status    string
+	WorkerId  string
+	LastError string
+}
+
+func taskKey(taskID string) string {
+	return "task:" + taskID
+}
+
+func meetingTasksKey(meetingID string) string {
+	return fmt.Sprintf("meeting:%s:tasks", meetingID)
+}
+
+func taskTypeKey(taskType string) string {
+	return "tasks:type:" + taskType
+}
+
+// addTaskScript records a pending task together with the message to publish
+// for it and queues it in the outbox. With a stage name in ARGV[8] it first
+// claims the stage for the meeting and records nothing if the stage was
+// already claimed, so a claim never exists without its task.
+//
+// KEYS: task, meeting tasks, type index, outbox, meeting stages
+// ARGV: task ID, meeting ID, type, queue, payload, now, outbox score, stage
+var addTaskScript = redis.NewScript(`
+if ARGV[8] ~= "" and redis.call("HSETNX", KEYS[5], ARGV[8], ARGV[6]) == 0 then
+	return 0
+end
+redis.call("HSET", KEYS[1],
+	"meeting_id", ARGV[2], "type", ARGV[3], "queue", ARGV[4], "status", "pending",
+	"attempts", 1, "payload", ARGV[5], "created_at", ARGV[6], "updated_at", ARGV[6])
+redis.call("SADD", KEYS[2], ARGV[1])
+redis.call("SADD", KEYS[3], ARGV[1])
+redis.call("ZADD", KEYS[4], ARGV[7], ARGV[1])
+return 1
+`)
+
+// AddTask records a pending task together with the message to publish for
+// it and queues it in the outbox, all in one step. The task is only
+// published by the outbox relay, so Redis never holds a task that will not
+// reach the broker.
+func (r *RedisManager) AddTask(ctx context.Context, meetingID, taskID, taskType, queue, payload string) error {
+	_, err := r.addTask(ctx, meetingID, taskID, taskType, queue, payload, "")
+	return err
+}
+
+// AddStageTask is AddTask for the task of a pipeline stage. It claims the
+// stage in the same step and reports false, recording nothing, if the stage
+// was already dispatched for the meeting.
+func (r *RedisManager) AddStageTask(ctx context.Context, meetingID, taskID, stage, queue, payload string) (bool, error) {
+	return r.addTask(ctx, meetingID, taskID, stage, queue, payload, stage)
+}
+
+func (r *RedisManager) addTask(ctx context.Context, meetingID, taskID, taskType, queue, payload, claim string) (bool, error) {
+	now := time.Now().UTC()
+	keys := []string{
+		taskKey(taskID),
+		meetingTasksKey(meetingID),
+		taskTypeKey(taskType),
+		outboxKey,
+		meetingStagesKey(meetingID),
+	}
+	added, err := addTaskScript.Run(ctx, r.Client, keys,
+		taskID, meetingID, taskType, queue, payload,
+		now.Format(time.RFC3339Nano), strconv.FormatFloat(outboxScore(now), 'f', 0, 64), claim,
+	).Int()
+	if err != nil {
+		return false, err
+	}
+	return added == 1, nil
+}
+
+// UpdateTask applies an update to an existing task of the given meeting.
+// Completed and failed tasks get a finished timestamp and leave the type
+// index.
+func (r *RedisManager) UpdateTask(ctx context.Context, meetingId, taskId string, update TaskUpdate) error {
+	values, err := r.Client.HMGet(ctx, taskKey(taskId), "meeting_id", "type").Result()
+	if err != nil {
+		return err
+	}
+	owner, _ := values[0].(string)
+	taskType, _ := values[1].(string)
+	if owner == "" || owner != meetingId {
+		return fmt.Errorf("%w: %s for meeting_id=%s", ErrTaskNotFound, taskId, meetingId)
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	fields := map[string]interface{}{
+		"status":     update.Status,
+		"updated_at": now,
+	}
+	finished := update.Status == "completed" || update.Status == "failed"
+	if finished {
+		fields["finished_at"] = now
+	}
+	if update.WorkerId != "" {
+		fields["worker_id"] = update.WorkerId
+	}
+	if update.LastError != "" {
+		fields["last_error"] = update.LastError
+	}
+
+	pipe := r.Client.TxPipeline()
+	pipe.HSet(ctx, taskKey(taskId), fields)
+	if finished {
+		pipe.SRem(ctx, taskTypeKey(taskType), taskId)
+	}
+	if _, err = pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	log.Printf("Updated Redis for meeting_id=%s, task_id=%s, status=%s", meetingId, taskId, update.Status)
+	return nil
+}
+
+// UnindexTask removes a task from the type index, e.g. a finished task that
+// was indexed before finished tasks left the index.
+func (r *RedisManager) UnindexTask(ctx context.Context, taskType, taskID string) error {
+	return r.Client.SRem(ctx, taskTypeKey(taskType), taskID).Err()
+}
+
+// RequeueTask puts an existing task back into the outbox to be published
+// again, bumping its attempt counter and updated timestamp.
+func (r *RedisManager) RequeueTask(ctx context.Context, taskID string) (int, error) {
+	now := time.Now().UTC()
+
+	pipe := r.Client.TxPipeline()
+	attempts := pipe.HIncrBy(ctx, taskKey(taskID), "attempts", 1)
+	pipe.HSet(ctx, taskKey(taskID), "updated_at", now.Format(time.RFC3339Nano))
+	pipe.HDel(ctx, taskKey(taskID), "sent_at")
+	pipe.ZAdd(ctx, outboxKey, &redis.Z{Score: outboxScore(now), Member: taskID})
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return int(attempts.Val()), nil
+}
+
+func (r *RedisManager) GetTask(ctx context.Context, taskID string) (TaskInfo, error) {
+	fields, err := r.Client.HGetAll(ctx, taskKey(taskID)).Result()
+	if err != nil {
+		return TaskInfo{}, err
+	}
+	if len(fields) == 0 {
+		return TaskInfo{}, fmt.Errorf("%w: %s", ErrTaskNotFound, taskID)
+	}
+	return parseTask(taskID, fields), nil
+}
+
+// GetTasks returns every task of a meeting ordered by creation time.
+func (r *RedisManager) GetTasks(ctx context.Context, meetingID string) ([]TaskInfo, error) {
+	taskIDs, err := r.Client.SMembers(ctx, meetingTasksKey(meetingID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	return r.getTasks(ctx, taskIDs)
+}
+
+// GetTasksOfType returns the unfinished tasks of the given type across all
+// meetings.
+func (r *RedisManager) GetTasksOfType(ctx context.Context, taskType string) ([]TaskInfo, error) {
+	taskIDs, err := r.Client.SMembers(ctx, taskTypeKey(taskType)).Result()
+	if err != nil {
+		return nil, err
+	}
+	return r.getTasks(ctx, taskIDs)
+}
+
+func (r *RedisManager) getTasks(ctx context.Context, taskIDs []string) ([]TaskInfo, error) {
+	pipe := r.Client.Pipeline()
+	cmds := make([]*redis.StringStringMapCmd, len(taskIDs))
+	for i, taskID := range taskIDs {
+		cmds[i] = pipe.HGetAll(ctx, taskKey(taskID))
+	}
+	if len(taskIDs) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]TaskInfo, 0, len(taskIDs))
+	for i, taskID := range taskIDs {
+		fields := cmds[i].Val()
+		if len(fields) == 0 {
+			continue
+		}
+		result = append(result, parseTask(taskID, fields))
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+
+	return result, nil
+}
+
+func parseTask(taskID string, fields map[string]string) TaskInfo {
+	task := TaskInfo{
+		TaskId:    taskID,
+		MeetingId: fields["meeting_id"],
+		TaskType:  fields["type"],
+		Queue:     fields["queue"],
+		Status:    fields["status"],
+		WorkerId:  fields["worker_id"],
+		LastError: fields["last_error"],
+	}
+	if payload := fields["payload"]; payload != "" {
+		task.Payload = json.RawMessage(payload)
+	}
+	task.Attempts, _ = strconv.Atoi(fields["attempts"])
+	task.CreatedAt, _ = time.Parse(time.RFC3339Nano, fields["created_at"])
+	task.UpdatedAt, _ = time.Parse(time.RFC3339Nano, fields["updated_at"])
+	if sent, err := time.Parse(time.RFC3339Nano, fields["sent_at"]); err == nil {
+		task.SentAt = &sent
+	}
+	if finished, err := time.Parse(time.RFC3339Nano, fields["finished_at"]); err == nil {
+		task.FinishedAt = &finished
+	}
+	return task
+}
+
+// deleteMeetingTasks removes the task records of a meeting and drops them
+// from the type indexes.
+func (r *RedisManager) deleteMeetingTasks(ctx context.Context, meetingID string) error {
+	tasks, err := r.GetTasks(ctx, meetingID)
+	if err != nil {
+		return err
+	}
+
+	pipe := r.Client.TxPipeline()
+	for _, task := range tasks {
+		pipe.ZRem(ctx, outboxKey, task.TaskId)
+		pipe.SRem(ctx, taskTypeKey(task.TaskType), task.TaskId)
+		pipe.Del(ctx, taskKey(task.TaskId))
+	}
+	if len(tasks) == 0 {
+		return nil
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
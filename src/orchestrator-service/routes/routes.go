@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"messaging"
 	"net/http"
 	"orchestrator-service/handlers"
 
@@ -14,9 +15,10 @@ func Routes(app *handlers.Config) *chi.Mux {
 
 	// Middleware
 	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
+		AllowedOrigins:   app.AllowedOrigins,
+		AllowedMethods:   []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Meeting-Token", "Upload-Offset"},
+		ExposedHeaders:   []string{"Location", "Upload-Offset", "Upload-Length"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
@@ -27,10 +29,19 @@ func Routes(app *handlers.Config) *chi.Mux {
 	r.Get("/test", func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte("Test endpoint working"))
 	})
+	r.Get("/healthz", messaging.HealthHandler(app.Broker))
 	r.Get("/generate-meeting-id", app.GenerateMeetingId)
 	r.Post("/capture-screenshots", app.CaptureScreenshots)
 	r.Post("/capture-audio", app.CaptureAudio)
 	r.Post("/end-meeting", app.EndMeeting)
+	r.Post("/meetings", app.CreateMeeting)
+	r.Post("/meetings/import", app.ImportMeeting)
+	r.Post("/uploads", app.CreateUpload)
+	r.Head("/uploads/{id}", app.UploadOffset)
+	r.Patch("/uploads/{id}", app.AppendUpload)
+	r.Get("/meetings/{id}/status", app.GetMeetingStatus)
+	r.Get("/meetings/{id}/events", app.StreamMeetingEvents)
+	r.Get("/meetings/{id}/report", app.DownloadReport)
 
 	return r
 }
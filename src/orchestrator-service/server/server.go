@@ -0,0 +1,79 @@
+package server
+
+import (
+	"messaging"
+	"net/http"
+	"orchestrator-service/config"
+	"orchestrator-service/events"
+	"orchestrator-service/handlers"
+	"orchestrator-service/pipeline"
+	"orchestrator-service/queues"
+	"orchestrator-service/redis"
+	"orchestrator-service/routes"
+	"storage"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+)
+
+// Options are the backing services the orchestrator runs on.
+type Options struct {
+	Store    storage.Store
+	Broker   messaging.Broker
+	Redis    *goredis.Client
+	Workflow *pipeline.Workflow
+}
+
+// Start declares the queues, starts the background loops and the ack and
+// retry consumers, and returns the handler of the HTTP API.
+func Start(options Options) (http.Handler, error) {
+	// The RabbitMQ broker re-declares the queues every time it reconnects.
+	retryPolicy := config.TaskRetryPolicy()
+	err := queues.DeclareQueues(options.Broker, []string{
+		"logs_queue",
+		"orchestrator_ack_queue",
+		queues.ParkingQueue("orchestrator_ack_queue"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = queues.DeclareTaskQueues(options.Broker, options.Workflow.Queues(), retryPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	redisManager := &redis.RedisManager{Client: options.Redis}
+	eventBroker := events.NewBroker()
+
+	taskHandler := handlers.NewTaskHandler(options.Broker, redisManager, eventBroker, options.Workflow)
+
+	appConfig := &handlers.Config{
+		Store:           options.Store,
+		Broker:          options.Broker,
+		RedisManager:    redisManager,
+		TaskHandler:     taskHandler,
+		Events:          eventBroker,
+		Workflow:        options.Workflow,
+		ReportRetention: config.ReportRetention(),
+		TokenSecret:     config.MeetingTokenSecret(),
+		AllowedOrigins:  config.AllowedOrigins(),
+	}
+
+	go handlers.CleanupExpiredReports(appConfig.ReportRetention, 10*time.Minute)
+	go appConfig.CleanupExpiredUploads(10 * time.Minute)
+	go appConfig.RunTaskWatchdog(time.Minute)
+	go taskHandler.RunOutboxRelay(time.Second)
+
+	consumer := &queues.Consumer{Broker: options.Broker, Parked: taskHandler.TaskParked}
+	consumer.ConsumeOrdered(
+		"orchestrator_ack_queue",
+		config.AckConsumerOptions(),
+		handlers.AckMeetingKey,
+		handlers.HandleAckMessage(redisManager, appConfig),
+	)
+	for _, queue := range options.Workflow.Queues() {
+		consumer.RouteDeadLetters(queue, retryPolicy)
+	}
+
+	return routes.Routes(appConfig), nil
+}
@@ -2,21 +2,28 @@ module report-service
 
 go 1.23.1
 
-require (
-	github.com/jung-kurt/gofpdf v1.16.2
-	github.com/streadway/amqp v1.1.0
-	go.mongodb.org/mongo-driver v1.17.2
-)
+require github.com/jung-kurt/gofpdf v1.16.2
 
 require (
+	contracts v0.0.0
 	github.com/golang/snappy v0.0.4 // indirect
 	github.com/klauspost/compress v1.16.7 // indirect
 	github.com/montanaflynn/stats v0.7.1 // indirect
+	github.com/streadway/amqp v1.1.0 // indirect
 	github.com/xdg-go/pbkdf2 v1.0.0 // indirect
 	github.com/xdg-go/scram v1.1.2 // indirect
 	github.com/xdg-go/stringprep v1.0.4 // indirect
 	github.com/youmark/pkcs8 v0.0.0-20240726163527-a2c0da244d78 // indirect
+	go.mongodb.org/mongo-driver v1.17.2 // indirect
 	golang.org/x/crypto v0.26.0 // indirect
 	golang.org/x/sync v0.8.0 // indirect
 	golang.org/x/text v0.17.0 // indirect
+	messaging v0.0.0
+	storage v0.0.0
 )
+
+replace contracts => ../contracts
+
+replace messaging => ../messaging
+
+replace storage => ../storage
@@ -0,0 +1,136 @@
+package report
+
+import (
+	"contracts"
+	"fmt"
+	"log"
+	"messaging"
+	"os"
+)
+
+func (app *Config) sendAckMessage(task contracts.TaskMessage, status string, taskErr error) error {
+	ackMessage := contracts.NewAckMessage(task, status, workerId(), taskErr)
+
+	body, err := contracts.Encode(&ackMessage)
+	if err != nil {
+		return fmt.Errorf("failed to encode ack message: %w", err)
+	}
+
+	err = app.Broker.Publish(
+		"orchestrator_ack_queue",
+		messaging.Message{
+			ContentType: "application/json",
+			Body:        body,
+		},
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to publish ack message: %w", err)
+	}
+
+	log.Printf("Ack message sent to orchestrator_ack_queue: %s", string(body))
+	return nil
+}
+
+// workerId identifies the instance that handled a task; inside a container
+// the hostname is the container ID.
+func workerId() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+	return hostname
+}
+
+func (app *Config) publishLog(logMessage contracts.LogMessage) error {
+	body, err := contracts.Encode(&logMessage)
+	if err != nil {
+		return fmt.Errorf("failed to encode log message: %w", err)
+	}
+
+	err = app.Broker.Publish(
+		"logs_queue",
+		messaging.Message{
+			ContentType: "application/json",
+			Body:        body,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to publish log: %w", err)
+	}
+
+	log.Println("Log sent to broker:", string(body))
+	return nil
+}
+
+// declareQueues declares the queues the service uses. The task queue options
+// must match the orchestrator's declaration.
+func declareQueues(broker messaging.Broker) error {
+	return broker.Declare(
+		messaging.Queue{Name: "report_queue", DeadLetter: true},
+		messaging.Queue{Name: "orchestrator_ack_queue"},
+		messaging.Queue{Name: "logs_queue"},
+	)
+}
+
+func (app *Config) handleReportTask(msg messaging.Delivery) {
+	log.Printf("Received task: %s", msg.Body)
+
+	task, err := contracts.DecodeTaskMessage(msg.Body)
+	if err != nil {
+		log.Printf("Rejecting task message: %v", err)
+		_ = msg.Nack(false)
+		return
+	}
+
+	log.Printf("Processing task with meeting_id: %s", task.MeetingId)
+
+	transcriptions, summary, ocrResults, err := app.fetchMeetingData(task.MeetingId)
+	if err != nil {
+		log.Printf("Error fetching data for meeting_id %s: %v", task.MeetingId, err)
+		_ = app.sendAckMessage(task, contracts.StatusFailed, err)
+		_ = msg.Nack(false)
+		return
+	}
+
+	meeting, err := app.fetchMeeting(task.MeetingId)
+	if err != nil {
+		log.Printf("Error fetching meeting record for meeting_id %s: %v", task.MeetingId, err)
+		_ = app.sendAckMessage(task, contracts.StatusFailed, err)
+		_ = msg.Nack(false)
+		return
+	}
+
+	statistics, err := app.fetchStatistics(task.MeetingId)
+	if err != nil {
+		log.Printf("Error fetching statistics for meeting_id %s: %v", task.MeetingId, err)
+		_ = app.sendAckMessage(task, contracts.StatusFailed, err)
+		_ = msg.Nack(false)
+		return
+	}
+
+	screenshots, err := fetchScreenshots(task.MeetingId)
+	if err != nil {
+		log.Printf("Error fetching screenshots for meeting_id %s: %v", task.MeetingId, err)
+		_ = app.sendAckMessage(task, contracts.StatusFailed, err)
+		_ = msg.Nack(false)
+		return
+	}
+
+	err = generatePDF(meeting, transcriptions, summary, statistics, ocrResults, screenshots)
+	if err != nil {
+		log.Printf("Error generating PDF for meeting_id %s: %v", task.MeetingId, err)
+		_ = app.sendAckMessage(task, contracts.StatusFailed, err)
+		_ = msg.Nack(false)
+		return
+	}
+
+	log.Printf("Successfully generated PDF for meeting_id: %s", task.MeetingId)
+
+	err = app.sendAckMessage(task, contracts.StatusCompleted, nil)
+	if err != nil {
+		log.Printf("Error sending acknowledgment message for meeting_id %s: %v", task.MeetingId, err)
+	}
+
+	_ = msg.Ack()
+}
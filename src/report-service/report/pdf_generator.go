@@ -0,0 +1,176 @@
+package report
+
+import (
+	"contracts"
+	_ "embed"
+	"fmt"
+	"github.com/jung-kurt/gofpdf"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The font is embedded so reports render the same wherever the worker runs.
+//
+//go:embed fonts/DejaVuSans.ttf
+var dejaVuSans []byte
+
+func generatePDF(meeting Meeting, transcriptions []Transcription, summary Summary, statistics *contracts.MeetingStatistics, ocrResults []OCRResult, screenshots []string) error {
+	meetingID := meeting.MeetingID
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes("DejaVu", "", dejaVuSans)
+	pdf.SetFont("DejaVu", "", 12)
+
+	pdf.AddPage()
+	if meeting.Title != "" {
+		pdf.Cell(40, 10, fmt.Sprintf("Meeting Report - %s", meeting.Title))
+		pdf.Ln(8)
+		pdf.Cell(40, 10, fmt.Sprintf("Meeting ID: %s", meetingID))
+	} else {
+		pdf.Cell(40, 10, fmt.Sprintf("Meeting Report - %s", meetingID))
+	}
+
+	if len(meeting.Participants) > 0 {
+		pdf.Ln(10)
+		pdf.Cell(0, 10, "Attendees:")
+		pdf.Ln(10)
+		pdf.MultiCell(0, 10, strings.Join(meeting.Participants, ", "), "", "", false)
+	}
+
+	if meeting.Agenda != "" {
+		pdf.Ln(10)
+		pdf.Cell(0, 10, "Agenda:")
+		pdf.Ln(10)
+		pdf.MultiCell(0, 10, meeting.Agenda, "", "", false)
+	}
+
+	pdf.SetFont("DejaVu", "", 12)
+	pdf.Ln(10)
+	pdf.Cell(0, 10, "Summary:")
+	pdf.Ln(10)
+	pdf.MultiCell(0, 10, summary.SummaryText, "", "", false)
+
+	if statistics != nil {
+		writeStatistics(pdf, statistics)
+	}
+
+	pdf.Ln(10)
+	pdf.Cell(0, 10, "Transcriptions:")
+	pdf.Ln(10)
+	for _, t := range transcriptions {
+		pdf.MultiCell(0, 10, fmt.Sprintf("[%s-%s] %s: %s", t.TimestampStart, t.TimestampEnd, t.SpeakerID, t.Transcription), "", "", false)
+		pdf.Ln(2)
+	}
+
+	pdf.Ln(10)
+	pdf.Cell(0, 10, "OCR Results:")
+	pdf.Ln(10)
+	for _, ocr := range ocrResults {
+		pdf.MultiCell(0, 10, ocr.TextResult, "", "", false)
+		pdf.Ln(2)
+	}
+
+	pdf.Ln(10)
+	pdf.Cell(0, 10, "Screenshots:")
+	pdf.Ln(10)
+
+	for _, screenshot := range screenshots {
+		imgHeight := pdf.GetY() + 105
+
+		if imgHeight > 297 {
+			pdf.AddPage()
+			pdf.Ln(10)
+		}
+
+		pdf.Image(screenshot, 10, pdf.GetY(), 180, 0, false, "", 0, "")
+
+		pdf.Ln(105)
+	}
+
+	outputDir := filepath.Join(contracts.SharedDir(contracts.ReportDir), meetingID)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("error creating directory: %v", err)
+	}
+
+	outputPath := filepath.Join(outputDir, fmt.Sprintf("meeting_report_%s.pdf", meetingID))
+
+	err := pdf.OutputFileAndClose(outputPath)
+	if err != nil {
+		return fmt.Errorf("error generating PDF: %v", err)
+	}
+
+	return nil
+}
+
+// statisticsColumns are the headers and widths in mm of the speaker table.
+var statisticsColumns = []struct {
+	header string
+	width  float64
+}{
+	{"Speaker", 38}, {"Words", 18}, {"Talk time", 22}, {"Turns", 16},
+	{"Words/turn", 24}, {"Longest turn", 26}, {"Interrupted", 24}, {"Interrupts", 22},
+}
+
+func writeStatistics(pdf *gofpdf.Fpdf, statistics *contracts.MeetingStatistics) {
+	pdf.Ln(10)
+	pdf.Cell(0, 10, "Statistics:")
+	pdf.Ln(10)
+
+	lines := []string{
+		fmt.Sprintf("Words: %d", statistics.TotalWords),
+		fmt.Sprintf("Talk time: %s", formatSeconds(statistics.TalkTimeSeconds)),
+		fmt.Sprintf("Speaker turns: %d", statistics.Turns),
+		fmt.Sprintf("Overlapping turns: %d, of which interruptions: %d", statistics.Overlaps, statistics.Interruptions),
+	}
+	if m := statistics.LongestMonologue; m != nil {
+		lines = append(lines, fmt.Sprintf("Longest monologue: %s, %s from %s (%d words)",
+			m.SpeakerID, formatSeconds(m.Seconds), m.TimestampStart, m.Words))
+	}
+	for _, line := range lines {
+		pdf.MultiCell(0, 8, line, "", "", false)
+	}
+
+	if len(statistics.Speakers) == 0 {
+		return
+	}
+
+	pdf.Ln(4)
+	pdf.SetFont("DejaVu", "", 10)
+	for _, column := range statisticsColumns {
+		pdf.CellFormat(column.width, 8, column.header, "1", 0, "C", false, 0, "")
+	}
+	pdf.Ln(-1)
+
+	for _, speaker := range statistics.Speakers {
+		cells := []string{
+			speaker.SpeakerID,
+			fmt.Sprintf("%d", speaker.Words),
+			formatSeconds(speaker.TalkTimeSeconds),
+			fmt.Sprintf("%d", speaker.Turns),
+			fmt.Sprintf("%.1f", speaker.AverageTurnWords),
+			formatSeconds(speaker.LongestTurnSeconds),
+			fmt.Sprintf("%d", speaker.Interrupted),
+			fmt.Sprintf("%d", speaker.Interruptions),
+		}
+		for i, cell := range cells {
+			align := "R"
+			if i == 0 {
+				align = "L"
+			}
+			pdf.CellFormat(statisticsColumns[i].width, 8, cell, "1", 0, align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	pdf.SetFont("DejaVu", "", 12)
+}
+
+// formatSeconds writes durations as m:ss, or h:mm:ss from an hour on.
+func formatSeconds(seconds float64) string {
+	total := int(math.Round(seconds))
+	if total >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", total/3600, total%3600/60, total%60)
+	}
+	return fmt.Sprintf("%d:%02d", total/60, total%60)
+}
@@ -0,0 +1,131 @@
+package report
+
+import (
+	"context"
+	"contracts"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"storage"
+	"time"
+)
+
+type Transcription struct {
+	SpeakerID      string `bson:"speaker_id"`
+	Transcription  string `bson:"transcription"`
+	TimestampStart string `bson:"timestamp_start"`
+	TimestampEnd   string `bson:"timestamp_end"`
+	MeetingID      string `bson:"meeting_id"`
+}
+
+type Summary struct {
+	MeetingID   string `bson:"meeting_id"`
+	SummaryText string `bson:"summary_text"`
+}
+
+type Meeting struct {
+	MeetingID    string   `bson:"meeting_id"`
+	Title        string   `bson:"title"`
+	Agenda       string   `bson:"agenda"`
+	Language     string   `bson:"language"`
+	Participants []string `bson:"participants"`
+}
+
+type OCRResult struct {
+	TextResult string `bson:"text_result"`
+	MeetingID  string `bson:"meeting_id"`
+}
+
+func (app *Config) fetchMeetingData(meetingID string) ([]Transcription, Summary, []OCRResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := storage.Filter{"meeting_id": meetingID}
+
+	log.Printf("Fetching transcriptions for meeting_id: %s", meetingID)
+	transcriptionsColl := app.Store.Collection("database", "transcriptions")
+	var transcriptions []Transcription
+	err := transcriptionsColl.Find(ctx, filter, "timestamp_start", &transcriptions)
+	if err != nil {
+		return nil, Summary{}, nil, fmt.Errorf("error fetching transcriptions: %w", err)
+	}
+	log.Printf("Fetched %d transcriptions", len(transcriptions))
+
+	log.Printf("Fetching summary for meeting_id: %s", meetingID)
+	summariesColl := app.Store.Collection("database", "summaries")
+	var summary Summary
+	err = summariesColl.FindOne(ctx, filter, &summary)
+	if err != nil {
+		return nil, Summary{}, nil, fmt.Errorf("error fetching summary: %w", err)
+	}
+	log.Println("Fetched summary")
+
+	log.Printf("Fetching OCR results for meeting_id: %s", meetingID)
+	ocrResultsColl := app.Store.Collection("database", "ocr_results")
+	var ocrResults []OCRResult
+	err = ocrResultsColl.Find(ctx, filter, "", &ocrResults)
+	if err != nil {
+		return nil, Summary{}, nil, fmt.Errorf("error fetching OCR results: %w", err)
+	}
+	log.Printf("Fetched %d OCR results", len(ocrResults))
+
+	return transcriptions, summary, ocrResults, nil
+}
+
+// fetchMeeting returns an empty Meeting for meetings started without a
+// meeting record.
+func (app *Config) fetchMeeting(meetingID string) (Meeting, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	log.Printf("Fetching meeting record for meeting_id: %s", meetingID)
+	var meeting Meeting
+	err := app.Store.Collection("database", "meetings").FindOne(ctx, storage.Filter{"meeting_id": meetingID}, &meeting)
+	if errors.Is(err, storage.ErrNotFound) {
+		log.Printf("No meeting record for meeting_id: %s", meetingID)
+		return Meeting{MeetingID: meetingID}, nil
+	}
+	if err != nil {
+		return Meeting{}, fmt.Errorf("error fetching meeting record: %w", err)
+	}
+
+	return meeting, nil
+}
+
+// fetchStatistics returns nil for meetings summarized before the statistics
+// were computed.
+func (app *Config) fetchStatistics(meetingID string) (*contracts.MeetingStatistics, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	log.Printf("Fetching statistics for meeting_id: %s", meetingID)
+	var statistics contracts.MeetingStatistics
+	err := app.Store.Collection("database", "statistics").FindOne(ctx, storage.Filter{"meeting_id": meetingID}, &statistics)
+	if errors.Is(err, storage.ErrNotFound) {
+		log.Printf("No statistics for meeting_id: %s", meetingID)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching statistics: %w", err)
+	}
+
+	return &statistics, nil
+}
+
+func fetchScreenshots(meetingID string) ([]string, error) {
+	screenshotsDir := filepath.Join(contracts.SharedDir(contracts.OCRDir), meetingID)
+	files, err := ioutil.ReadDir(screenshotsDir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading screenshots directory: %v", err)
+	}
+
+	var screenshotPaths []string
+	for _, file := range files {
+		if !file.IsDir() {
+			screenshotPaths = append(screenshotPaths, filepath.Join(screenshotsDir, file.Name()))
+		}
+	}
+	return screenshotPaths, nil
+}
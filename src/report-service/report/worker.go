@@ -0,0 +1,25 @@
+package report
+
+import (
+	"messaging"
+	"storage"
+)
+
+type Config struct {
+	Store  storage.Store
+	Broker messaging.Broker
+}
+
+// Start declares the queues and starts consuming report tasks.
+func (app *Config) Start() error {
+	if err := declareQueues(app.Broker); err != nil {
+		return err
+	}
+
+	app.Broker.Consume(messaging.Consumer{
+		Queue:    "report_queue",
+		Prefetch: 1,
+		Handler:  app.handleReportTask,
+	})
+	return nil
+}
@@ -0,0 +1,54 @@
+module standalone
+
+go 1.23.1
+
+require (
+	contracts v0.0.0
+	github.com/alicebob/miniredis/v2 v2.33.0
+	github.com/go-redis/redis/v8 v8.11.5
+	logger-service v0.0.0
+	messaging v0.0.0
+	mock-llm v0.0.0
+	orchestrator-service v0.0.0
+	report-service v0.0.0
+	storage v0.0.0
+	summary-service v0.0.0
+)
+
+require (
+	github.com/alicebob/gopher-json v0.0.0-20200520072559-a9ecdc9d1d3a // indirect
+	github.com/cespare/xxhash/v2 v2.1.2 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/go-chi/chi/v5 v5.1.0 // indirect
+	github.com/go-chi/cors v1.2.1 // indirect
+	github.com/golang/snappy v0.0.4 // indirect
+	github.com/jung-kurt/gofpdf v1.16.2 // indirect
+	github.com/klauspost/compress v1.16.7 // indirect
+	github.com/montanaflynn/stats v0.7.1 // indirect
+	github.com/streadway/amqp v1.1.0 // indirect
+	github.com/xdg-go/pbkdf2 v1.0.0 // indirect
+	github.com/xdg-go/scram v1.1.2 // indirect
+	github.com/xdg-go/stringprep v1.0.4 // indirect
+	github.com/youmark/pkcs8 v0.0.0-20240726163527-a2c0da244d78 // indirect
+	github.com/yuin/gopher-lua v1.1.1 // indirect
+	go.mongodb.org/mongo-driver v1.17.2 // indirect
+	golang.org/x/crypto v0.26.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+	golang.org/x/text v0.17.0 // indirect
+)
+
+replace contracts => ../contracts
+
+replace logger-service => ../logger-service
+
+replace messaging => ../messaging
+
+replace mock-llm => ../mock-llm
+
+replace orchestrator-service => ../orchestrator-service
+
+replace report-service => ../report-service
+
+replace storage => ../storage
+
+replace summary-service => ../summary-service
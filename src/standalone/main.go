@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"logger-service/sink"
+	"messaging"
+	"mock-llm/mock"
+	"net"
+	"net/http"
+	"orchestrator-service/config"
+	"orchestrator-service/pipeline"
+	"orchestrator-service/server"
+	"os"
+	"report-service/report"
+	"storage"
+	"summary-service/summary"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/go-redis/redis/v8"
+)
+
+const (
+	defaultListenAddr     = ":8080"
+	defaultSharedDataRoot = "data"
+)
+
+// The standalone binary runs the orchestrator API, the ack handling, the
+// summary and report workers and the log sink in one process. The queue and
+// the storage are embedded unless BROKER_URL, STORAGE_URL or REDIS_ADDR
+// point at real services.
+func main() {
+	setDefaultEnv("BROKER_URL", "memory://")
+	setDefaultEnv("STORAGE_URL", "memory://")
+	setDefaultEnv("SHARED_DATA_ROOT", defaultSharedDataRoot)
+
+	store, err := storage.Open(storage.URLFromEnv())
+	if err != nil {
+		log.Fatalf("Failed to open storage: %v", err)
+	}
+	defer store.Close(context.Background())
+
+	broker, err := messaging.Open(messaging.URLFromEnv())
+	if err != nil {
+		log.Fatalf("Failed to open message broker: %v", err)
+	}
+	defer broker.Close()
+
+	redisClient, err := connectToRedis()
+	if err != nil {
+		log.Fatalf("Failed to start Redis: %v", err)
+	}
+	defer redisClient.Close()
+
+	workflow, err := pipeline.Load(os.Getenv("PIPELINE_DEFINITION"))
+	if err != nil {
+		log.Fatalf("Failed to load pipeline definition: %v", err)
+	}
+
+	handler, err := server.Start(server.Options{
+		Store:    store,
+		Broker:   broker,
+		Redis:    redisClient,
+		Workflow: workflow,
+	})
+	if err != nil {
+		log.Fatalf("Failed to start orchestrator: %v", err)
+	}
+
+	if err = startMockLLM(); err != nil {
+		log.Fatalf("Failed to start mock LLM: %v", err)
+	}
+
+	if err = (&summary.Config{Store: store, Broker: broker}).Start(); err != nil {
+		log.Fatalf("Failed to start summary worker: %v", err)
+	}
+	if err = (&report.Config{Store: store, Broker: broker}).Start(); err != nil {
+		log.Fatalf("Failed to start report worker: %v", err)
+	}
+	if err = (&sink.Config{Store: store, Broker: broker}).Start(); err != nil {
+		log.Fatalf("Failed to start log sink: %v", err)
+	}
+
+	if err = startPythonWorkers(store, broker); err != nil {
+		log.Fatalf("Failed to start Python service stubs: %v", err)
+	}
+
+	addr := os.Getenv("LISTEN_ADDR")
+	if addr == "" {
+		addr = defaultListenAddr
+	}
+
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: handler,
+	}
+
+	log.Printf("Standalone pipeline is ready and listening on %s (broker %s, shared data in %s).",
+		addr, broker.Status().Backend, os.Getenv("SHARED_DATA_ROOT"))
+	if err = srv.ListenAndServe(); err != nil {
+		log.Fatalf("Error starting server: %v", err)
+	}
+}
+
+func setDefaultEnv(key, value string) {
+	if os.Getenv(key) == "" {
+		os.Setenv(key, value)
+	}
+}
+
+// connectToRedis uses the Redis at REDIS_ADDR if it is set and an embedded
+// in-memory Redis otherwise.
+func connectToRedis() (*goredis.Client, error) {
+	if addr := os.Getenv("REDIS_ADDR"); addr != "" {
+		return config.ConnectToRedis(addr)
+	}
+
+	embedded, err := miniredis.Run()
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Started embedded Redis on %s", embedded.Addr())
+	return config.ConnectToRedis(embedded.Addr())
+}
+
+// startMockLLM serves the offline mock LLM on a loopback port and points the
+// summary worker at it, unless the LLM is configured: by LLM_CONFIG,
+// LLM_PROVIDER or LLM_API_URL, or by GROQ_API_KEY for the default provider.
+func startMockLLM() error {
+	for _, key := range []string{"LLM_CONFIG", "LLM_PROVIDER", "LLM_API_URL", "GROQ_API_KEY"} {
+		if os.Getenv(key) != "" {
+			return nil
+		}
+	}
+
+	options, err := mock.OptionsFromEnv()
+	if err != nil {
+		return err
+	}
+	handler, err := mock.NewServer(options)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := http.Serve(listener, handler); err != nil {
+			log.Printf("Mock LLM stopped: %v", err)
+		}
+	}()
+
+	url := fmt.Sprintf("http://%s/v1/chat/completions", listener.Addr())
+	os.Setenv("LLM_API_URL", url)
+	log.Printf("No LLM is configured, summaries come from the mock LLM at %s", url)
+	return nil
+}
+
+// startPythonWorkers starts the stubs of the Python services, unless
+// PYTHON_WORKERS=external says the real services consume the task queues.
+// They can only do so through RabbitMQ and MongoDB, so BROKER_URL and
+// STORAGE_URL have to point at those.
+func startPythonWorkers(store storage.Store, broker messaging.Broker) error {
+	switch mode := os.Getenv("PYTHON_WORKERS"); mode {
+	case "", "stub":
+		stubs, err := newStubWorkers(store, broker, os.Getenv("TRANSCRIPTION_FIXTURE"))
+		if err != nil {
+			return err
+		}
+		return stubs.Start()
+	case "external":
+		if broker.Status().Backend != "rabbitmq" {
+			return fmt.Errorf("PYTHON_WORKERS=external needs a RabbitMQ BROKER_URL")
+		}
+		log.Println("Expecting the transcription, OCR and email services to consume their queues.")
+		return nil
+	default:
+		return fmt.Errorf("unsupported PYTHON_WORKERS %q", mode)
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"contracts"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"messaging"
+	"os"
+	"path/filepath"
+	"storage"
+	"strings"
+	"time"
+)
+
+const stubWorkerId = "standalone-stub"
+
+//go:embed fixtures/transcription.json
+var defaultTranscriptionFixture []byte
+
+// fixtureSegment is one transcribed utterance in the layout the
+// transcription service writes to the transcriptions collection.
+type fixtureSegment struct {
+	SpeakerID      string `json:"speaker_id" bson:"speaker_id"`
+	Transcription  string `json:"transcription" bson:"transcription"`
+	TimestampStart string `json:"timestamp_start" bson:"timestamp_start"`
+	TimestampEnd   string `json:"timestamp_end" bson:"timestamp_end"`
+	MeetingID      string `json:"meeting_id" bson:"meeting_id"`
+}
+
+type ocrResult struct {
+	TextResult string `bson:"text_result"`
+	MeetingID  string `bson:"meeting_id"`
+}
+
+// stubWorkers stand in for the Python transcription, OCR and email services.
+// They acknowledge every task like the real services do and write fixture
+// results, so a meeting runs through the whole pipeline without models or an
+// SMTP server.
+type stubWorkers struct {
+	Store    storage.Store
+	Broker   messaging.Broker
+	Segments []fixtureSegment
+}
+
+func newStubWorkers(store storage.Store, broker messaging.Broker, fixturePath string) (*stubWorkers, error) {
+	data := defaultTranscriptionFixture
+	if fixturePath != "" {
+		var err error
+		if data, err = os.ReadFile(fixturePath); err != nil {
+			return nil, err
+		}
+	}
+
+	var segments []fixtureSegment
+	if err := json.Unmarshal(data, &segments); err != nil {
+		return nil, fmt.Errorf("failed to parse transcription fixture: %w", err)
+	}
+
+	return &stubWorkers{Store: store, Broker: broker, Segments: segments}, nil
+}
+
+// Start consumes the queues of the stages handled by the Python services.
+func (s *stubWorkers) Start() error {
+	handlers := map[string]func(context.Context, contracts.TaskMessage) error{
+		"transcription_queue": s.transcribe,
+		"ocr_queue":           s.recognize,
+		"email_queue":         s.email,
+	}
+
+	for queue, handler := range handlers {
+		err := s.Broker.Declare(messaging.Queue{Name: queue, DeadLetter: true})
+		if err != nil {
+			return err
+		}
+
+		handler := handler
+		s.Broker.Consume(messaging.Consumer{
+			Queue:    queue,
+			Prefetch: 1,
+			Handler: func(msg messaging.Delivery) {
+				s.handleTask(msg, handler)
+			},
+		})
+	}
+	return nil
+}
+
+func (s *stubWorkers) handleTask(msg messaging.Delivery, handler func(context.Context, contracts.TaskMessage) error) {
+	task, err := contracts.DecodeTaskMessage(msg.Body)
+	if err != nil {
+		log.Printf("Stub rejecting task message: %v", err)
+		_ = msg.Nack(false)
+		return
+	}
+
+	status := contracts.StatusCompleted
+	err = handler(context.Background(), task)
+	if err != nil {
+		log.Printf("Stub %s task %s failed: %v", task.TaskType, task.TaskId, err)
+		status = contracts.StatusFailed
+	}
+
+	if ackErr := s.sendAckMessage(task, status, err); ackErr != nil {
+		log.Printf("Stub failed to acknowledge task %s: %v", task.TaskId, ackErr)
+	}
+
+	if err != nil {
+		_ = msg.Nack(false)
+		return
+	}
+	_ = msg.Ack()
+}
+
+func (s *stubWorkers) sendAckMessage(task contracts.TaskMessage, status string, taskErr error) error {
+	ackMessage := contracts.NewAckMessage(task, status, stubWorkerId, taskErr)
+
+	body, err := contracts.Encode(&ackMessage)
+	if err != nil {
+		return err
+	}
+
+	return s.Broker.Publish("orchestrator_ack_queue", messaging.Message{
+		ContentType: "application/json",
+		Body:        body,
+	})
+}
+
+// transcribe appends the fixture segments after the meeting's latest
+// transcription, as the transcription service does for each audio fragment.
+func (s *stubWorkers) transcribe(ctx context.Context, task contracts.TaskMessage) error {
+	collection := s.Store.Collection("database", "transcriptions")
+
+	var existing []fixtureSegment
+	err := collection.Find(ctx, storage.Filter{"meeting_id": task.MeetingId}, "timestamp_end", &existing)
+	if err != nil {
+		return err
+	}
+
+	var offset time.Duration
+	if len(existing) > 0 {
+		offset = parseTimestamp(existing[len(existing)-1].TimestampEnd)
+	}
+
+	for _, segment := range s.Segments {
+		segment.MeetingID = task.MeetingId
+		segment.TimestampStart = contracts.FormatTimestamp(offset + parseTimestamp(segment.TimestampStart))
+		segment.TimestampEnd = contracts.FormatTimestamp(offset + parseTimestamp(segment.TimestampEnd))
+		if err = collection.InsertOne(ctx, segment); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("Stub transcribed %s for meeting_id=%s", task.FilePath, task.MeetingId)
+	return nil
+}
+
+func (s *stubWorkers) recognize(ctx context.Context, task contracts.TaskMessage) error {
+	result := ocrResult{
+		TextResult: fmt.Sprintf("Text recognized on %s (stub)", filepath.Base(task.FilePath)),
+		MeetingID:  task.MeetingId,
+	}
+
+	log.Printf("Stub recognized %s for meeting_id=%s", task.FilePath, task.MeetingId)
+	return s.Store.Collection("database", "ocr_results").InsertOne(ctx, result)
+}
+
+func (s *stubWorkers) email(ctx context.Context, task contracts.TaskMessage) error {
+	recipients := task.Emails
+	if len(recipients) == 0 && task.Email != "" {
+		recipients = []string{task.Email}
+	}
+
+	log.Printf("Stub would send %s to %s", task.FilePath, strings.Join(recipients, ", "))
+	return nil
+}
+
+// parseTimestamp counts timestamps the transcription service would not
+// write as zero.
+func parseTimestamp(value string) time.Duration {
+	d, _ := contracts.ParseTimestamp(value)
+	return d
+}
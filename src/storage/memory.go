@@ -0,0 +1,268 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryStore is an in-process Store. Documents are kept BSON-encoded, so
+// they decode exactly like documents read from MongoDB, but they live only as
+// long as the process.
+type MemoryStore struct {
+	mu          sync.RWMutex
+	collections map[string]*memoryCollection
+}
+
+type memoryCollection struct {
+	store     *MemoryStore
+	documents []bson.Raw
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{collections: make(map[string]*memoryCollection)}
+}
+
+func (s *MemoryStore) Collection(database, name string) Collection {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := database + "." + name
+	collection, ok := s.collections[key]
+	if !ok {
+		collection = &memoryCollection{store: s}
+		s.collections[key] = collection
+	}
+	return collection
+}
+
+func (s *MemoryStore) Close(ctx context.Context) error {
+	return nil
+}
+
+func (c *memoryCollection) InsertOne(ctx context.Context, document interface{}) error {
+	raw, err := withID(document)
+	if err != nil {
+		return err
+	}
+
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	c.documents = append(c.documents, raw)
+	return nil
+}
+
+func (c *memoryCollection) Find(ctx context.Context, filter Filter, sortField string, results interface{}) error {
+	target := reflect.ValueOf(results)
+	if target.Kind() != reflect.Ptr || target.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("results must be a pointer to a slice, got %T", results)
+	}
+
+	matches, err := c.find(filter)
+	if err != nil {
+		return err
+	}
+
+	if sortField != "" {
+		sort.SliceStable(matches, func(i, j int) bool {
+			return lessValue(matches[i].Lookup(sortField), matches[j].Lookup(sortField))
+		})
+	}
+
+	slice := reflect.MakeSlice(target.Elem().Type(), len(matches), len(matches))
+	for i, raw := range matches {
+		if err = bson.Unmarshal(raw, slice.Index(i).Addr().Interface()); err != nil {
+			return err
+		}
+	}
+	target.Elem().Set(slice)
+	return nil
+}
+
+func (c *memoryCollection) FindOne(ctx context.Context, filter Filter, result interface{}) error {
+	matches, err := c.find(filter)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return ErrNotFound
+	}
+	return bson.Unmarshal(matches[0], result)
+}
+
+func (c *memoryCollection) Upsert(ctx context.Context, filter Filter, fields map[string]interface{}) error {
+	values, err := rawFilter(filter)
+	if err != nil {
+		return err
+	}
+
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	for i, raw := range c.documents {
+		if !matches(raw, values) {
+			continue
+		}
+
+		var document bson.D
+		if err = bson.Unmarshal(raw, &document); err != nil {
+			return err
+		}
+		updated, err := bson.Marshal(setFields(document, fields))
+		if err != nil {
+			return err
+		}
+		c.documents[i] = updated
+		return nil
+	}
+
+	document := bson.D{}
+	for key, value := range filter {
+		document = append(document, bson.E{Key: key, Value: value})
+	}
+	raw, err := withID(setFields(document, fields))
+	if err != nil {
+		return err
+	}
+	c.documents = append(c.documents, raw)
+	return nil
+}
+
+func (c *memoryCollection) DeleteMany(ctx context.Context, filter Filter) (int64, error) {
+	values, err := rawFilter(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	kept := c.documents[:0]
+	var deleted int64
+	for _, raw := range c.documents {
+		if matches(raw, values) {
+			deleted++
+			continue
+		}
+		kept = append(kept, raw)
+	}
+	c.documents = kept
+	return deleted, nil
+}
+
+func (c *memoryCollection) find(filter Filter) ([]bson.Raw, error) {
+	values, err := rawFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+
+	var found []bson.Raw
+	for _, raw := range c.documents {
+		if matches(raw, values) {
+			found = append(found, raw)
+		}
+	}
+	return found, nil
+}
+
+// rawFilter encodes the filter values so they compare equal to the values of
+// stored documents regardless of their Go type.
+func rawFilter(filter Filter) (bson.Raw, error) {
+	return bson.Marshal(bson.M(filter))
+}
+
+func matches(document, filter bson.Raw) bool {
+	elements, err := filter.Elements()
+	if err != nil {
+		return false
+	}
+	for _, element := range elements {
+		value, err := document.LookupErr(element.Key())
+		if err != nil || !value.Equal(element.Value()) {
+			return false
+		}
+	}
+	return true
+}
+
+func setFields(document bson.D, fields map[string]interface{}) bson.D {
+	for key, value := range fields {
+		replaced := false
+		for i := range document {
+			if document[i].Key == key {
+				document[i].Value = value
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			document = append(document, bson.E{Key: key, Value: value})
+		}
+	}
+	return document
+}
+
+// withID encodes document and adds an ObjectID _id like MongoDB does if the
+// document has none.
+func withID(document interface{}) (bson.Raw, error) {
+	raw, err := bson.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = bson.Raw(raw).LookupErr("_id"); err == nil {
+		return raw, nil
+	}
+
+	var fields bson.D
+	if err = bson.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	return bson.Marshal(append(bson.D{{Key: "_id", Value: primitive.NewObjectID()}}, fields...))
+}
+
+// lessValue orders strings, numbers and dates; missing values sort first.
+func lessValue(a, b bson.RawValue) bool {
+	switch {
+	case a.Type == 0:
+		return b.Type != 0
+	case b.Type == 0:
+		return false
+	}
+
+	if x, ok := number(a); ok {
+		if y, ok := number(b); ok {
+			return x < y
+		}
+	}
+	if a.Type == bsontype.DateTime && b.Type == bsontype.DateTime {
+		return a.DateTime() < b.DateTime()
+	}
+	if x, ok := a.StringValueOK(); ok {
+		if y, ok := b.StringValueOK(); ok {
+			return x < y
+		}
+	}
+	return a.Type < b.Type
+}
+
+func number(value bson.RawValue) (float64, bool) {
+	switch value.Type {
+	case bsontype.Double:
+		return value.Double(), true
+	case bsontype.Int32:
+		return float64(value.Int32()), true
+	case bsontype.Int64:
+		return float64(value.Int64()), true
+	}
+	return 0, false
+}
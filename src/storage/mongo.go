@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	retries    = 5
+	retryDelay = 5 * time.Second
+)
+
+// MongoStore is the MongoDB Store.
+type MongoStore struct {
+	Client *mongo.Client
+}
+
+type mongoCollection struct {
+	collection *mongo.Collection
+}
+
+// ConnectMongo connects to MongoDB, retrying while the database is starting.
+func ConnectMongo(uri string) (*MongoStore, error) {
+	var client *mongo.Client
+	var err error
+
+	for i := 0; i < retries; i++ {
+		client, err = mongo.NewClient(options.Client().ApplyURI(uri))
+		if err != nil {
+			log.Printf("MongoDB client creation error: %v", err)
+			time.Sleep(retryDelay)
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err = client.Connect(ctx)
+		cancel()
+		if err == nil {
+			log.Println("Successfully connected to MongoDB.")
+			return &MongoStore{Client: client}, nil
+		}
+
+		log.Printf("MongoDB connection failed (%d/%d): %v", i+1, retries, err)
+		time.Sleep(retryDelay)
+	}
+
+	return nil, fmt.Errorf("failed to connect to MongoDB after %d retries: %w", retries, err)
+}
+
+func (s *MongoStore) Collection(database, name string) Collection {
+	return &mongoCollection{collection: s.Client.Database(database).Collection(name)}
+}
+
+func (s *MongoStore) Close(ctx context.Context) error {
+	return s.Client.Disconnect(ctx)
+}
+
+func (c *mongoCollection) InsertOne(ctx context.Context, document interface{}) error {
+	_, err := c.collection.InsertOne(ctx, document)
+	return err
+}
+
+func (c *mongoCollection) Find(ctx context.Context, filter Filter, sortField string, results interface{}) error {
+	opts := options.Find()
+	if sortField != "" {
+		opts.SetSort(bson.D{{Key: sortField, Value: 1}})
+	}
+
+	cursor, err := c.collection.Find(ctx, bson.M(filter), opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	return cursor.All(ctx, results)
+}
+
+func (c *mongoCollection) FindOne(ctx context.Context, filter Filter, result interface{}) error {
+	err := c.collection.FindOne(ctx, bson.M(filter)).Decode(result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (c *mongoCollection) Upsert(ctx context.Context, filter Filter, fields map[string]interface{}) error {
+	update := bson.M{"$set": bson.M(fields)}
+	_, err := c.collection.UpdateOne(ctx, bson.M(filter), update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (c *mongoCollection) DeleteMany(ctx context.Context, filter Filter) (int64, error) {
+	result, err := c.collection.DeleteMany(ctx, bson.M(filter))
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,133 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	requestTimeout = 2 * time.Minute
+	// A rate limited request is sent up to maxRateLimitAttempts times. Groq
+	// limits tokens per minute, so waits of up to a minute are expected.
+	maxRateLimitAttempts = 6
+	minRateLimitDelay    = 2 * time.Second
+	maxRateLimitDelay    = time.Minute
+)
+
+// chatClient talks to an OpenAI-compatible /chat/completions endpoint. Groq,
+// OpenAI, Azure OpenAI, Ollama and the llama.cpp server only differ in the
+// URL and in how the API key is sent.
+type chatClient struct {
+	name   string
+	url    string
+	header string
+	apiKey string
+	client *http.Client
+}
+
+func newChatClient(name, url, header, apiKey string) *chatClient {
+	return &chatClient{
+		name:   name,
+		url:    url,
+		header: header,
+		apiKey: apiKey,
+		client: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Complete sends the request, retrying it while the provider rate limits it
+// with status 429. The wait is taken from the Retry-After header when the
+// provider sends one.
+func (c *chatClient) Complete(ctx context.Context, request Request) (string, error) {
+	requestBodyJSON, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+
+	delay := minRateLimitDelay
+	for attempt := 1; ; attempt++ {
+		resp, body, err := c.post(ctx, requestBodyJSON)
+		if err != nil {
+			return "", err
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < maxRateLimitAttempts {
+			wait := retryAfter(resp.Header, delay)
+			log.Printf("%s rate limited the request, retrying in %s", c.name, wait)
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(wait):
+			}
+			delay = min(2*delay, maxRateLimitDelay)
+			continue
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return "", &StatusError{Provider: c.name, StatusCode: resp.StatusCode, Body: string(bytes.TrimSpace(body))}
+		}
+
+		var response responseBody
+		if err = json.Unmarshal(body, &response); err != nil {
+			return "", err
+		}
+
+		if len(response.Choices) == 0 {
+			return "", ErrNoResponse
+		}
+		return response.Choices[0].Message.Content, nil
+	}
+}
+
+func (c *chatClient) post(ctx context.Context, requestBodyJSON []byte) (*http.Response, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(requestBodyJSON))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		if c.header == "Authorization" {
+			req.Header.Set(c.header, "Bearer "+c.apiKey)
+		} else {
+			req.Header.Set(c.header, c.apiKey)
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, body, nil
+}
+
+// retryAfter reads the Retry-After header, given in seconds or as an HTTP
+// date, and falls back to fallback without one.
+func retryAfter(header http.Header, fallback time.Duration) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return fallback
+	}
+
+	var wait time.Duration
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		wait = time.Duration(seconds * float64(time.Second))
+	} else if date, err := http.ParseTime(value); err == nil {
+		wait = time.Until(date)
+	} else {
+		return fallback
+	}
+	return min(max(wait, 0), maxRateLimitDelay)
+}
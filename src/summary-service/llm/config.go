@@ -0,0 +1,332 @@
+package llm
+
+import (
+	"contracts"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultTemperature     = 0.7
+	defaultMaxTokens       = 500
+	defaultAzureAPIVersion = "2024-06-01"
+	// promptTokens is reserved in the context window for the instructions
+	// around the transcript.
+	promptTokens   = 400
+	minChunkTokens = 256
+)
+
+// Provider types.
+const (
+	TypeGroq     = "groq"
+	TypeOpenAI   = "openai"
+	TypeAzure    = "azure"
+	TypeOllama   = "ollama"
+	TypeLlamaCpp = "llamacpp"
+)
+
+type providerType struct {
+	url             string
+	model           string
+	contextWindow   int
+	tokensPerMinute int
+	// hosted providers need an API key unless the URL is overridden, e.g.
+	// to point at a proxy or at the mock LLM server.
+	hosted bool
+}
+
+var providerTypes = map[string]providerType{
+	// Groq's on-demand tier limits llama-3.1-8b-instant to 6000 tokens per
+	// minute, far below the model's context window.
+	TypeGroq:     {url: "https://api.groq.com/openai/v1/chat/completions", model: "llama-3.1-8b-instant", contextWindow: 131072, tokensPerMinute: 6000, hosted: true},
+	TypeOpenAI:   {url: "https://api.openai.com/v1/chat/completions", model: "gpt-4o-mini", contextWindow: 128000, hosted: true},
+	TypeAzure:    {contextWindow: 128000, hosted: true},
+	TypeOllama:   {url: "http://localhost:11434/v1/chat/completions", model: "llama3.1", contextWindow: 4096},
+	TypeLlamaCpp: {url: "http://localhost:8080/v1/chat/completions", contextWindow: 4096},
+}
+
+// ProviderConfig configures one named provider. The API key is taken from
+// APIKey, the file APIKeyFile (e.g. a Docker secret) or the environment
+// variable APIKeyEnv, in that order, each time a summary is generated.
+type ProviderConfig struct {
+	Type string `json:"type"`
+	// URL of the chat completions endpoint, by default the public API of the
+	// type or the default port of a local server.
+	URL string `json:"url"`
+	// Model is used when the settings name none.
+	Model string `json:"model"`
+	// ContextWindow is the number of tokens a prompt and its completion may
+	// use together. Longer transcripts are summarized in chunks.
+	ContextWindow int `json:"context_window"`
+	// TokensPerMinute is the rate limit of the account, if any. A request
+	// larger than the limit is always rejected, so chunks are kept below it.
+	TokensPerMinute int    `json:"tokens_per_minute"`
+	APIKey          string `json:"api_key"`
+	APIKeyFile      string `json:"api_key_file"`
+	APIKeyEnv       string `json:"api_key_env"`
+	// Endpoint, Deployment and APIVersion build the URL of an Azure
+	// OpenAI deployment.
+	Endpoint   string `json:"endpoint"`
+	Deployment string `json:"deployment"`
+	APIVersion string `json:"api_version"`
+}
+
+// Config holds the providers of a deployment and the settings used for
+// meetings that do not choose their own.
+type Config struct {
+	Default   contracts.SummarySettings `json:"default"`
+	Providers map[string]ProviderConfig `json:"providers"`
+}
+
+// Load reads the JSON configuration at path, or builds the configuration
+// from environment variables if path is empty.
+func Load(path string) (*Config, error) {
+	if path == "" {
+		return FromEnv()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read LLM configuration: %w", err)
+	}
+
+	var config Config
+	if err = json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse LLM configuration: %w", err)
+	}
+
+	if err = config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// FromEnv configures one provider of every type. LLM_PROVIDER, LLM_MODEL,
+// LLM_TEMPERATURE and LLM_MAX_TOKENS set the defaults, and LLM_API_URL,
+// LLM_CONTEXT_WINDOW and LLM_TOKENS_PER_MINUTE override the URL, context
+// window and rate limit of the default provider.
+func FromEnv() (*Config, error) {
+	config := Config{
+		Default: contracts.SummarySettings{
+			Provider: os.Getenv("LLM_PROVIDER"),
+			Model:    os.Getenv("LLM_MODEL"),
+		},
+		Providers: map[string]ProviderConfig{
+			TypeGroq:   {Type: TypeGroq, APIKeyEnv: "GROQ_API_KEY"},
+			TypeOpenAI: {Type: TypeOpenAI, APIKeyEnv: "OPENAI_API_KEY"},
+			TypeAzure: {
+				Type:       TypeAzure,
+				Endpoint:   os.Getenv("AZURE_OPENAI_ENDPOINT"),
+				Deployment: os.Getenv("AZURE_OPENAI_DEPLOYMENT"),
+				APIVersion: os.Getenv("AZURE_OPENAI_API_VERSION"),
+				APIKeyEnv:  "AZURE_OPENAI_API_KEY",
+			},
+			TypeOllama:   {Type: TypeOllama, URL: os.Getenv("OLLAMA_URL")},
+			TypeLlamaCpp: {Type: TypeLlamaCpp, URL: os.Getenv("LLAMACPP_URL")},
+		},
+	}
+
+	if config.Default.Provider == "" {
+		config.Default.Provider = TypeGroq
+	}
+
+	if value := os.Getenv("LLM_TEMPERATURE"); value != "" {
+		temperature, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LLM_TEMPERATURE %q", value)
+		}
+		config.Default.Temperature = &temperature
+	}
+
+	if value := os.Getenv("LLM_MAX_TOKENS"); value != "" {
+		maxTokens, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LLM_MAX_TOKENS %q", value)
+		}
+		config.Default.MaxTokens = maxTokens
+	}
+
+	if provider, ok := config.Providers[config.Default.Provider]; ok {
+		if url := os.Getenv("LLM_API_URL"); url != "" {
+			provider.URL = url
+		}
+		if value := os.Getenv("LLM_CONTEXT_WINDOW"); value != "" {
+			contextWindow, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid LLM_CONTEXT_WINDOW %q", value)
+			}
+			provider.ContextWindow = contextWindow
+		}
+		if value := os.Getenv("LLM_TOKENS_PER_MINUTE"); value != "" {
+			tokensPerMinute, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid LLM_TOKENS_PER_MINUTE %q", value)
+			}
+			provider.TokensPerMinute = tokensPerMinute
+		}
+		config.Providers[config.Default.Provider] = provider
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func (c *Config) Validate() error {
+	for name, provider := range c.Providers {
+		if _, ok := providerTypes[provider.Type]; !ok {
+			return fmt.Errorf("LLM provider %s has unsupported type %q", name, provider.Type)
+		}
+		if provider.ContextWindow < 0 {
+			return fmt.Errorf("LLM provider %s has a negative context window", name)
+		}
+		if provider.TokensPerMinute < 0 {
+			return fmt.Errorf("LLM provider %s has a negative rate limit", name)
+		}
+	}
+	if _, ok := c.Providers[c.Default.Provider]; !ok {
+		return fmt.Errorf("default LLM provider %q is not configured", c.Default.Provider)
+	}
+	return c.Default.Validate()
+}
+
+// Target is the provider and the request parameters resolved for a meeting.
+type Target struct {
+	Provider
+	// Request carries the model and the sampling parameters; the caller adds
+	// the messages.
+	Request         Request
+	ContextWindow   int
+	TokensPerMinute int
+}
+
+// ChunkTokens is how many tokens of transcript fit into one prompt next to
+// the instructions and the completion. Prompts are also kept within the rate
+// limit, which would otherwise reject them however long the client waits.
+func (t *Target) ChunkTokens() int {
+	limit := t.ContextWindow
+	if t.TokensPerMinute > 0 && t.TokensPerMinute < limit {
+		limit = t.TokensPerMinute
+	}
+	return max(limit-t.Request.MaxTokens-promptTokens, minChunkTokens)
+}
+
+// Resolve applies the settings of a meeting over the defaults.
+func (c *Config) Resolve(override contracts.SummarySettings) (*Target, error) {
+	settings := c.Default.Merge(override)
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+
+	config, ok := c.Providers[settings.Provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown LLM provider %q", settings.Provider)
+	}
+
+	provider, err := config.provider(settings.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	target := &Target{
+		Provider: provider,
+		Request: Request{
+			Model:       settings.Model,
+			MaxTokens:   settings.MaxTokens,
+			Temperature: defaultTemperature,
+		},
+		ContextWindow:   config.ContextWindow,
+		TokensPerMinute: config.TokensPerMinute,
+	}
+	if target.Request.Model == "" {
+		target.Request.Model = config.model()
+	}
+	if target.Request.MaxTokens == 0 {
+		target.Request.MaxTokens = defaultMaxTokens
+	}
+	if settings.Temperature != nil {
+		target.Request.Temperature = *settings.Temperature
+	}
+	if target.ContextWindow == 0 {
+		target.ContextWindow = providerTypes[config.Type].contextWindow
+	}
+	if target.TokensPerMinute == 0 {
+		target.TokensPerMinute = providerTypes[config.Type].tokensPerMinute
+	}
+	return target, nil
+}
+
+func (p *ProviderConfig) provider(name string) (Provider, error) {
+	apiKey, err := p.apiKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the API key of LLM provider %s: %w", name, err)
+	}
+
+	url := p.URL
+	custom := url != ""
+	if !custom {
+		url, err = p.defaultURL(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if providerTypes[p.Type].hosted && !custom && apiKey == "" {
+		if p.APIKeyEnv != "" {
+			return nil, fmt.Errorf("%s is not set", p.APIKeyEnv)
+		}
+		return nil, fmt.Errorf("LLM provider %s has no API key", name)
+	}
+
+	if p.Type == TypeAzure {
+		return newChatClient(name, url, "api-key", apiKey), nil
+	}
+	return newChatClient(name, url, "Authorization", apiKey), nil
+}
+
+func (p *ProviderConfig) defaultURL(name string) (string, error) {
+	if p.Type != TypeAzure {
+		return providerTypes[p.Type].url, nil
+	}
+
+	if p.Endpoint == "" || p.Deployment == "" {
+		return "", fmt.Errorf("LLM provider %s needs an Azure endpoint and deployment", name)
+	}
+	version := p.APIVersion
+	if version == "" {
+		version = defaultAzureAPIVersion
+	}
+	return fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+		strings.TrimRight(p.Endpoint, "/"), p.Deployment, version), nil
+}
+
+func (p *ProviderConfig) model() string {
+	if p.Model != "" {
+		return p.Model
+	}
+	if p.Type == TypeAzure {
+		// Azure picks the model by deployment and ignores the field.
+		return p.Deployment
+	}
+	return providerTypes[p.Type].model
+}
+
+func (p *ProviderConfig) apiKey() (string, error) {
+	switch {
+	case p.APIKey != "":
+		return p.APIKey, nil
+	case p.APIKeyFile != "":
+		data, err := os.ReadFile(p.APIKeyFile)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(data)), nil
+	case p.APIKeyEnv != "":
+		return os.Getenv(p.APIKeyEnv), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,49 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNoResponse is returned when a provider answers without any choices.
+var ErrNoResponse = errors.New("no response from model")
+
+// Provider generates chat completions with one LLM backend.
+type Provider interface {
+	Complete(ctx context.Context, req Request) (string, error)
+}
+
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Request is the OpenAI chat completions request, which every supported
+// backend accepts.
+type Request struct {
+	Model       string    `json:"model"`
+	Messages    []Message `json:"messages"`
+	MaxTokens   int       `json:"max_tokens"`
+	Temperature float64   `json:"temperature"`
+}
+
+type responseBody struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	} `json:"choices"`
+}
+
+// StatusError is returned when a provider answers with a status other than
+// 200, e.g. 429 when it rate limits the requests.
+type StatusError struct {
+	Provider   string
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s returned status %d: %s", e.Provider, e.StatusCode, e.Body)
+}
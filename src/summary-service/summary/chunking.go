@@ -0,0 +1,155 @@
+package summary
+
+import (
+	"fmt"
+	"strings"
+)
+
+// charsPerToken approximates how much English text one token covers with the
+// tokenizers of the supported models.
+const charsPerToken = 4
+
+func estimateTokens(text string) int {
+	return (len(text) + charsPerToken - 1) / charsPerToken
+}
+
+// chunk is a part of the transcript that is summarized with one prompt.
+type chunk struct {
+	Start    string
+	End      string
+	Segments []Transcription
+	tokens   int
+}
+
+func (c *chunk) add(segments []Transcription, tokens int) {
+	if len(c.Segments) == 0 {
+		c.Start = segments[0].TimestampStart
+	}
+	c.Segments = append(c.Segments, segments...)
+	c.End = segments[len(segments)-1].TimestampEnd
+	c.tokens += tokens
+}
+
+func (c *chunk) text() string {
+	return formatTranscript(c.Segments)
+}
+
+// formatTranscript renders segments the way they appear in the prompt: one
+// line per speaker turn, labelled with the speaker and the time the turn
+// starts, e.g. "[0:12:05] Speaker B: ...".
+func formatTranscript(segments []Transcription) string {
+	var text strings.Builder
+	for _, turn := range speakerTurns(segments) {
+		fmt.Fprintf(&text, "[%s] %s:", shortTimestamp(turn[0].TimestampStart), speakerLabel(turn[0].SpeakerID))
+		for _, segment := range turn {
+			text.WriteString(" ")
+			text.WriteString(strings.TrimSpace(segment.Transcription))
+		}
+		text.WriteString("\n")
+	}
+	return text.String()
+}
+
+func segmentTokens(segments []Transcription) int {
+	return estimateTokens(formatTranscript(segments))
+}
+
+func speakerLabel(speakerId string) string {
+	if speakerId == "" {
+		return "Unknown speaker"
+	}
+	return speakerId
+}
+
+// shortTimestamp drops the fraction of a second from timestamps such as
+// "0:00:09.534375".
+func shortTimestamp(timestamp string) string {
+	if i := strings.IndexByte(timestamp, '.'); i >= 0 {
+		return timestamp[:i]
+	}
+	return timestamp
+}
+
+// speakerTurns groups consecutive segments of the same speaker.
+func speakerTurns(transcriptions []Transcription) [][]Transcription {
+	var turns [][]Transcription
+	for i, segment := range transcriptions {
+		if i > 0 && segment.SpeakerID == transcriptions[i-1].SpeakerID {
+			turns[len(turns)-1] = append(turns[len(turns)-1], segment)
+			continue
+		}
+		turns = append(turns, []Transcription{segment})
+	}
+	return turns
+}
+
+// splitTranscript cuts the transcript into chunks of at most budget tokens.
+// Chunks end between speaker turns; a turn that does not fit into a chunk of
+// its own is cut between its timestamped segments, and only a single segment
+// longer than a chunk is cut between words.
+func splitTranscript(transcriptions []Transcription, budget int) []chunk {
+	var chunks []chunk
+	var current chunk
+
+	flush := func() {
+		if len(current.Segments) > 0 {
+			chunks = append(chunks, current)
+			current = chunk{}
+		}
+	}
+
+	for _, turn := range speakerTurns(transcriptions) {
+		tokens := segmentTokens(turn)
+		if current.tokens+tokens <= budget {
+			current.add(turn, tokens)
+			continue
+		}
+
+		flush()
+		if tokens <= budget {
+			current.add(turn, tokens)
+			continue
+		}
+
+		for _, segment := range turn {
+			for _, part := range splitSegment(segment, budget) {
+				tokens := segmentTokens([]Transcription{part})
+				if current.tokens+tokens > budget {
+					flush()
+				}
+				current.add([]Transcription{part}, tokens)
+			}
+		}
+	}
+	flush()
+
+	return chunks
+}
+
+// splitSegment cuts a segment longer than budget tokens between words. The
+// parts keep the timestamps of the segment.
+func splitSegment(segment Transcription, budget int) []Transcription {
+	if segmentTokens([]Transcription{segment}) <= budget {
+		return []Transcription{segment}
+	}
+
+	var parts []Transcription
+	var words []string
+	length := 0
+	for _, word := range strings.Fields(segment.Transcription) {
+		if len(words) > 0 && (length+len(word)+charsPerToken)/charsPerToken > budget {
+			part := segment
+			part.Transcription = strings.Join(words, " ")
+			parts = append(parts, part)
+			words, length = nil, 0
+		}
+		words = append(words, word)
+		length += len(word) + 1
+	}
+	if len(words) > 0 {
+		part := segment
+		part.Transcription = strings.Join(words, " ")
+		parts = append(parts, part)
+	}
+	return parts
+}
@@ -0,0 +1,83 @@
+package summary
+
+import (
+	"context"
+	"contracts"
+	"errors"
+	"fmt"
+	"log"
+	"storage"
+	"strings"
+	"summary-service/llm"
+	"time"
+)
+
+func (app *Config) generateSummary(meetingId string) error {
+	transcriptions, err := app.fetchTranscriptions(meetingId)
+	if err != nil {
+		return err
+	}
+
+	statistics := computeStatistics(meetingId, transcriptions)
+	if err = app.saveStatistics(statistics); err != nil {
+		return err
+	}
+	log.Printf("Saved statistics for meeting_id %s: %d words, %d turns of %d speakers", meetingId, statistics.TotalWords, statistics.Turns, len(statistics.Speakers))
+
+	settings, err := app.fetchSummarySettings(meetingId)
+	if err != nil {
+		return err
+	}
+
+	target, err := app.LLM.Resolve(settings)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Generating summary for meeting_id %s with model %s", meetingId, target.Request.Model)
+	summaryText, err := summarizeTranscript(context.TODO(), target, transcriptions)
+	if err == nil && strings.TrimSpace(summaryText) == "" {
+		err = llm.ErrNoResponse
+	}
+	if errors.Is(err, llm.ErrNoResponse) {
+		log.Printf("No response from model for meeting_id %s", meetingId)
+
+		logMessage := contracts.NewLogMessage("summary", "WARNING", fmt.Sprintf("No response from model, meeting_id: %s", meetingId), map[string]interface{}{
+			"meeting_id": meetingId,
+			"queue":      "summary_queue",
+		})
+		if logErr := app.publishLog(logMessage); logErr != nil {
+			log.Printf("Error publishing log to RabbitMQ: %v", logErr)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	err = app.saveSummaryToDB(meetingId, summaryText)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Summary saved for meeting_id %s", meetingId)
+
+	logMessage := contracts.NewLogMessage("summary", "INFO", fmt.Sprintf("Summary with meeting_id: %s saved successfully", meetingId), map[string]interface{}{
+		"meeting_id": meetingId,
+		"queue":      "summary_queue",
+	})
+	if err = app.publishLog(logMessage); err != nil {
+		log.Printf("Error publishing log to RabbitMQ: %v", err)
+	}
+	return nil
+}
+
+func (app *Config) saveSummaryToDB(meetingID, summaryText string) error {
+	collection := app.Store.Collection("database", "summaries")
+
+	filter := storage.Filter{"meeting_id": meetingID}
+
+	return collection.Upsert(context.TODO(), filter, map[string]interface{}{
+		"summary_text": summaryText,
+		"created_at":   time.Now(),
+	})
+}
@@ -0,0 +1,102 @@
+package summary
+
+import (
+	"contracts"
+	"fmt"
+	"log"
+	"messaging"
+	"os"
+)
+
+func (app *Config) sendAckMessage(task contracts.TaskMessage, status string, taskErr error) error {
+	ackMessage := contracts.NewAckMessage(task, status, workerId(), taskErr)
+
+	body, err := contracts.Encode(&ackMessage)
+	if err != nil {
+		return fmt.Errorf("failed to encode ack message: %w", err)
+	}
+
+	err = app.Broker.Publish(
+		"orchestrator_ack_queue",
+		messaging.Message{
+			ContentType: "application/json",
+			Body:        body,
+		},
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to publish ack message: %w", err)
+	}
+
+	log.Printf("Ack message sent to orchestrator_ack_queue: %s", string(body))
+	return nil
+}
+
+// workerId identifies the instance that handled a task; inside a container
+// the hostname is the container ID.
+func workerId() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+	return hostname
+}
+
+// declareQueues declares the queues the service uses. The task queue options
+// must match the orchestrator's declaration.
+func declareQueues(broker messaging.Broker) error {
+	return broker.Declare(
+		messaging.Queue{Name: "summary_queue", DeadLetter: true},
+		messaging.Queue{Name: "orchestrator_ack_queue"},
+		messaging.Queue{Name: "logs_queue"},
+	)
+}
+
+func (app *Config) handleSummaryTask(msg messaging.Delivery) {
+	log.Printf("Received task: %s", msg.Body)
+
+	task, err := contracts.DecodeTaskMessage(msg.Body)
+	if err != nil {
+		log.Printf("Rejecting task message: %v", err)
+		_ = msg.Nack(false)
+		return
+	}
+
+	log.Printf("Processing task with meeting_id: %s", task.MeetingId)
+
+	err = app.generateSummary(task.MeetingId)
+	if err != nil {
+		log.Printf("Error generating summary for meeting_id %s: %v", task.MeetingId, err)
+		_ = app.sendAckMessage(task, contracts.StatusFailed, err)
+		_ = msg.Nack(false)
+		return
+	}
+
+	err = app.sendAckMessage(task, contracts.StatusCompleted, nil)
+	if err != nil {
+		log.Printf("Error sending acknowledgment message for meeting_id %s: %v", task.MeetingId, err)
+	}
+
+	_ = msg.Ack()
+}
+
+func (app *Config) publishLog(logMessage contracts.LogMessage) error {
+	body, err := contracts.Encode(&logMessage)
+	if err != nil {
+		return fmt.Errorf("failed to encode log message: %w", err)
+	}
+
+	err = app.Broker.Publish(
+		"logs_queue",
+		messaging.Message{
+			ContentType: "application/json",
+			Body:        body,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to publish log: %w", err)
+	}
+
+	log.Println("Log sent to broker:", string(body))
+	return nil
+}
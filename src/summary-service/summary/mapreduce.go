@@ -0,0 +1,154 @@
+package summary
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"summary-service/llm"
+)
+
+const systemPrompt = "You are a helpful assistant."
+
+// partSummary is the summary of consecutive parts of the transcript.
+type partSummary struct {
+	Start string
+	End   string
+	Text  string
+}
+
+// summarizeTranscript summarizes a transcript that fits into the context
+// window with a single prompt. Longer transcripts are cut into chunks that
+// are summarized one by one (map), and the chunk summaries are merged into
+// the final summary (reduce).
+func summarizeTranscript(ctx context.Context, target *llm.Target, transcriptions []Transcription) (string, error) {
+	budget := target.ChunkTokens()
+	chunks := splitTranscript(transcriptions, budget)
+	if len(chunks) <= 1 {
+		var transcript string
+		if len(chunks) == 1 {
+			transcript = chunks[0].text()
+		}
+		return complete(ctx, target, summaryPrompt(transcript))
+	}
+
+	log.Printf("Transcript is longer than %d tokens, summarizing it in %d chunks", budget, len(chunks))
+
+	parts := make([]partSummary, 0, len(chunks))
+	for i, c := range chunks {
+		text, err := complete(ctx, target, chunkPrompt(c, i+1, len(chunks)))
+		if err != nil {
+			return "", fmt.Errorf("failed to summarize chunk %d of %d: %w", i+1, len(chunks), err)
+		}
+		parts = append(parts, partSummary{Start: c.Start, End: c.End, Text: text})
+	}
+
+	// The chunk summaries may not fit into one prompt either, so they are
+	// combined in groups until they do.
+	for estimateTokens(formatParts(parts)) > budget && len(parts) > 1 {
+		var combined []partSummary
+		for _, group := range groupParts(parts, budget) {
+			if len(group) == 1 {
+				combined = append(combined, group[0])
+				continue
+			}
+			text, err := complete(ctx, target, combinePrompt(group))
+			if err != nil {
+				return "", fmt.Errorf("failed to combine chunk summaries: %w", err)
+			}
+			combined = append(combined, partSummary{Start: group[0].Start, End: group[len(group)-1].End, Text: text})
+		}
+		log.Printf("Combined %d chunk summaries into %d", len(parts), len(combined))
+		parts = combined
+	}
+
+	return complete(ctx, target, reducePrompt(parts))
+}
+
+func complete(ctx context.Context, target *llm.Target, prompt string) (string, error) {
+	request := target.Request
+	request.Messages = []llm.Message{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: prompt},
+	}
+	return target.Complete(ctx, request)
+}
+
+// groupParts packs consecutive summaries into groups of at most budget
+// tokens. Groups hold at least two summaries, so every round of combining
+// makes progress.
+func groupParts(parts []partSummary, budget int) [][]partSummary {
+	var groups [][]partSummary
+	var current []partSummary
+	tokens := 0
+	for _, part := range parts {
+		partTokens := estimateTokens(formatParts([]partSummary{part}))
+		if len(current) >= 2 && tokens+partTokens > budget {
+			groups = append(groups, current)
+			current, tokens = nil, 0
+		}
+		current = append(current, part)
+		tokens += partTokens
+	}
+	if len(current) == 1 && len(groups) > 0 {
+		groups[len(groups)-1] = append(groups[len(groups)-1], current[0])
+	} else if len(current) > 0 {
+		groups = append(groups, current)
+	}
+	return groups
+}
+
+func formatParts(parts []partSummary) string {
+	var text strings.Builder
+	for i, part := range parts {
+		fmt.Fprintf(&text, "Part %d (%s - %s):\n%s\n\n", i+1, shortTimestamp(part.Start), shortTimestamp(part.End), strings.TrimSpace(part.Text))
+	}
+	return text.String()
+}
+
+// transcriptFormat explains the transcript lines built by formatTranscript.
+const transcriptFormat = `Every line of the transcription is one speaker turn and starts with the time the turn begins and the speaker's label.`
+
+// attribution asks for speaker-attributed summaries. Speakers are only known
+// by the labels of the diarization, so the model must not invent names.
+const attribution = `Attribute the key points to the speakers who made them, and list the decisions and action items with the speakers who proposed, agreed to or own them, using the times to refer to the moment they were made. Refer to speakers by their labels exactly as they appear.`
+
+func summaryPrompt(transcript string) string {
+	return `
+Please provide a precise summary of the following meeting transcription. ` + transcriptFormat + `
+
+` + transcript + `
+` + attribution + `
+
+Return the response in a structured format, ensuring clarity and readability.
+`
+}
+
+func chunkPrompt(c chunk, n, total int) string {
+	return fmt.Sprintf(`
+The following is part %d of %d of a meeting transcription, from %s to %s. %s
+Summarize this part precisely: the topics discussed, the key points, and any decisions and action items.
+%s
+Do not add an introduction or a conclusion, the summaries of all parts will be combined later.
+
+%s`, n, total, shortTimestamp(c.Start), shortTimestamp(c.End), transcriptFormat, attribution, c.text())
+}
+
+func combinePrompt(parts []partSummary) string {
+	return `
+The following are summaries of consecutive parts of a meeting.
+Combine them into one precise summary of these parts, keeping all decisions and action items and the speakers and times they are attributed to.
+Do not add an introduction or a conclusion, it will be combined with the summaries of other parts later.
+
+` + formatParts(parts)
+}
+
+func reducePrompt(parts []partSummary) string {
+	return `
+Please provide a precise summary of a meeting from the following summaries of its consecutive parts:
+
+` + formatParts(parts) + attribution + `
+
+Return the response in a structured format, ensuring clarity and readability.
+`
+}
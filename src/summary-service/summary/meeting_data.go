@@ -0,0 +1,60 @@
+package summary
+
+import (
+	"context"
+	"contracts"
+	"errors"
+	"sort"
+	"storage"
+	"strings"
+)
+
+// Transcription struct represents a single document in the collection
+type Transcription struct {
+	ID             string `bson:"_id"`
+	SpeakerID      string `bson:"speaker_id"`
+	Transcription  string `bson:"transcription"`
+	TimestampStart string `bson:"timestamp_start"`
+	TimestampEnd   string `bson:"timestamp_end"`
+	MeetingID      string `bson:"meeting_id"`
+}
+
+// fetchTranscriptions returns the non-empty segments of the meeting in
+// order.
+func (app *Config) fetchTranscriptions(meetingId string) ([]Transcription, error) {
+	collection := app.Store.Collection("database", "transcriptions")
+
+	filter := storage.Filter{"meeting_id": meetingId}
+	var transcriptions []Transcription
+	if err := collection.Find(context.TODO(), filter, "timestamp_start", &transcriptions); err != nil {
+		return nil, err
+	}
+
+	segments := transcriptions[:0]
+	for _, t := range transcriptions {
+		if strings.TrimSpace(t.Transcription) != "" {
+			segments = append(segments, t)
+		}
+	}
+
+	// Sorting by the timestamp strings puts "10:00:00" before "9:00:00".
+	sort.SliceStable(segments, func(i, j int) bool {
+		return parseTimestamp(segments[i].TimestampStart) < parseTimestamp(segments[j].TimestampStart)
+	})
+	return segments, nil
+}
+
+// fetchSummarySettings returns the LLM settings chosen for the meeting. Meetings
+// without settings, or without a meeting record, use the deployment defaults.
+func (app *Config) fetchSummarySettings(meetingId string) (contracts.SummarySettings, error) {
+	collection := app.Store.Collection("database", "meetings")
+
+	var meeting struct {
+		Summary contracts.SummarySettings `bson:"summary"`
+	}
+	err := collection.FindOne(context.TODO(), storage.Filter{"meeting_id": meetingId}, &meeting)
+	if errors.Is(err, storage.ErrNotFound) {
+		return contracts.SummarySettings{}, nil
+	}
+	return meeting.Summary, err
+}
@@ -0,0 +1,152 @@
+package summary
+
+import (
+	"context"
+	"contracts"
+	"math"
+	"storage"
+	"strings"
+	"time"
+)
+
+// timedTurn is a speaker turn with its parsed times.
+type timedTurn struct {
+	Speaker  string
+	Start    time.Duration
+	End      time.Duration
+	EndStamp string
+	Segments []Transcription
+	Words    int
+}
+
+func (t *timedTurn) duration() time.Duration {
+	return max(t.End-t.Start, 0)
+}
+
+// computeStatistics derives the meeting statistics from the segments in
+// order, so the numbers in the report do not depend on the LLM. Timestamps
+// that cannot be parsed count as zero.
+func computeStatistics(meetingId string, segments []Transcription) contracts.MeetingStatistics {
+	statistics := contracts.MeetingStatistics{
+		MeetingID: meetingId,
+		CreatedAt: time.Now(),
+	}
+
+	speakers := make(map[string]*contracts.SpeakerStatistics)
+	var order []string
+	speaker := func(label string) *contracts.SpeakerStatistics {
+		if _, ok := speakers[label]; !ok {
+			speakers[label] = &contracts.SpeakerStatistics{SpeakerID: label}
+			order = append(order, label)
+		}
+		return speakers[label]
+	}
+
+	talkTime := make(map[string]time.Duration)
+	var turns []timedTurn
+	for _, turn := range speakerTurns(segments) {
+		t := timedTurn{
+			Speaker:  speakerLabel(turn[0].SpeakerID),
+			Start:    parseTimestamp(turn[0].TimestampStart),
+			Segments: turn,
+		}
+		for _, segment := range turn {
+			start, end := parseTimestamp(segment.TimestampStart), parseTimestamp(segment.TimestampEnd)
+			if end >= t.End {
+				t.End, t.EndStamp = end, segment.TimestampEnd
+			}
+			t.Words += len(strings.Fields(segment.Transcription))
+			talkTime[t.Speaker] += max(end-start, 0)
+		}
+		turns = append(turns, t)
+
+		s := speaker(t.Speaker)
+		s.Words += t.Words
+		s.Turns++
+		s.LongestTurnSeconds = math.Max(s.LongestTurnSeconds, seconds(t.duration()))
+	}
+
+	var longest *timedTurn
+	for i := range turns {
+		turn := &turns[i]
+		if longest == nil || turn.duration() > longest.duration() {
+			longest = turn
+		}
+
+		// The turn overlaps the earlier turn of another speaker that ends
+		// last, if that one is still going on when the turn starts.
+		var overlapped *timedTurn
+		for j := 0; j < i; j++ {
+			other := &turns[j]
+			if other.Speaker != turn.Speaker && other.End > turn.Start && (overlapped == nil || other.End > overlapped.End) {
+				overlapped = other
+			}
+		}
+		if overlapped == nil {
+			continue
+		}
+
+		statistics.Overlaps++
+		if overlapped.End < turn.End {
+			statistics.Interruptions++
+			speakers[turn.Speaker].Interruptions++
+			speakers[overlapped.Speaker].Interrupted++
+		}
+	}
+
+	for _, label := range order {
+		s := speakers[label]
+		s.TalkTimeSeconds = seconds(talkTime[label])
+		s.AverageTurnWords = round(float64(s.Words) / float64(s.Turns))
+		s.AverageTurnSeconds = round(s.TalkTimeSeconds / float64(s.Turns))
+
+		statistics.TotalWords += s.Words
+		statistics.TalkTimeSeconds += s.TalkTimeSeconds
+		statistics.Speakers = append(statistics.Speakers, *s)
+	}
+	statistics.TalkTimeSeconds = round(statistics.TalkTimeSeconds)
+	statistics.Turns = len(turns)
+
+	if longest != nil {
+		statistics.LongestMonologue = &contracts.Monologue{
+			SpeakerID:      longest.Speaker,
+			TimestampStart: longest.Segments[0].TimestampStart,
+			TimestampEnd:   longest.EndStamp,
+			Seconds:        seconds(longest.duration()),
+			Words:          longest.Words,
+		}
+	}
+
+	return statistics
+}
+
+func (app *Config) saveStatistics(statistics contracts.MeetingStatistics) error {
+	collection := app.Store.Collection("database", "statistics")
+
+	filter := storage.Filter{"meeting_id": statistics.MeetingID}
+
+	return collection.Upsert(context.TODO(), filter, map[string]interface{}{
+		"total_words":       statistics.TotalWords,
+		"talk_time_seconds": statistics.TalkTimeSeconds,
+		"turns":             statistics.Turns,
+		"overlaps":          statistics.Overlaps,
+		"interruptions":     statistics.Interruptions,
+		"longest_monologue": statistics.LongestMonologue,
+		"speakers":          statistics.Speakers,
+		"created_at":        statistics.CreatedAt,
+	})
+}
+
+func parseTimestamp(value string) time.Duration {
+	d, _ := contracts.ParseTimestamp(value)
+	return d
+}
+
+func seconds(d time.Duration) float64 {
+	return round(d.Seconds())
+}
+
+// round keeps two decimals.
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
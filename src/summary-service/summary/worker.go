@@ -0,0 +1,37 @@
+package summary
+
+import (
+	"messaging"
+	"os"
+	"storage"
+	"summary-service/llm"
+)
+
+type Config struct {
+	Store  storage.Store
+	Broker messaging.Broker
+	// LLM is loaded from LLM_CONFIG, or from the environment, if nil.
+	LLM *llm.Config
+}
+
+// Start declares the queues and starts consuming summary tasks.
+func (app *Config) Start() error {
+	if app.LLM == nil {
+		config, err := llm.Load(os.Getenv("LLM_CONFIG"))
+		if err != nil {
+			return err
+		}
+		app.LLM = config
+	}
+
+	if err := declareQueues(app.Broker); err != nil {
+		return err
+	}
+
+	app.Broker.Consume(messaging.Consumer{
+		Queue:    "summary_queue",
+		Prefetch: 1,
+		Handler:  app.handleSummaryTask,
+	})
+	return nil
+}
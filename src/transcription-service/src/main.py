@@ -1,4 +1,5 @@
 import logging
+import socket
 from pyannote.audio import Pipeline
 from pyannote.audio import Model
 from pyannote.core import Segment
@@ -296,7 +297,7 @@ def main_consumer():
         try:
             logging.info("Connecting to RabbitMQ...")
             connection = pika.BlockingConnection(pika.ConnectionParameters(
-                host='rabbitmq',
+                host=os.environ.get('RABBITMQ_HOST', 'rabbitmq'),
                 port=5672,
                 credentials=credentials,
                 connection_attempts=5,
@@ -308,7 +309,11 @@ def main_consumer():
             ack_channel = connection.channel()
             logging.info("Connected to RabbitMQ")
 
-            channel.queue_declare(queue='transcription_queue', durable=True)
+            channel.queue_declare(
+                queue='transcription_queue',
+                durable=True,
+                arguments={'x-dead-letter-exchange': 'tasks.dlx'}
+            )
             logging.info("Queue declared.")
 
             channel.basic_qos(prefetch_count=1)
@@ -333,6 +338,8 @@ def main_consumer():
 
 def send_ack_message(message, ack_channel):
     try:
+        message.setdefault("schema_version", 1)
+        message.setdefault("worker_id", socket.gethostname())
         ack_channel.queue_declare(queue='orchestrator_ack_queue', durable=True)
 
         ack_channel.basic_publish(